@@ -29,6 +29,12 @@ type CTRLMessage struct {
 	//	*CTRLMessage_ConfigFileResponse
 	//	*CTRLMessage_Shutdown
 	//	*CTRLMessage_BenchmarkFinished
+	//	*CTRLMessage_Prepare
+	//	*CTRLMessage_PrepareDone
+	//	*CTRLMessage_StartAt
+	//	*CTRLMessage_StepReport
+	//	*CTRLMessage_CapacityReport
+	//	*CTRLMessage_TraceChunk
 	Payload       isCTRLMessage_Payload `protobuf_oneof:"payload"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -116,6 +122,60 @@ func (x *CTRLMessage) GetBenchmarkFinished() *BenchmarkFinished {
 	return nil
 }
 
+func (x *CTRLMessage) GetPrepare() *Prepare {
+	if x != nil {
+		if x, ok := x.Payload.(*CTRLMessage_Prepare); ok {
+			return x.Prepare
+		}
+	}
+	return nil
+}
+
+func (x *CTRLMessage) GetPrepareDone() *PrepareDone {
+	if x != nil {
+		if x, ok := x.Payload.(*CTRLMessage_PrepareDone); ok {
+			return x.PrepareDone
+		}
+	}
+	return nil
+}
+
+func (x *CTRLMessage) GetStartAt() *StartAt {
+	if x != nil {
+		if x, ok := x.Payload.(*CTRLMessage_StartAt); ok {
+			return x.StartAt
+		}
+	}
+	return nil
+}
+
+func (x *CTRLMessage) GetStepReport() *StepReport {
+	if x != nil {
+		if x, ok := x.Payload.(*CTRLMessage_StepReport); ok {
+			return x.StepReport
+		}
+	}
+	return nil
+}
+
+func (x *CTRLMessage) GetCapacityReport() *CapacityReport {
+	if x != nil {
+		if x, ok := x.Payload.(*CTRLMessage_CapacityReport); ok {
+			return x.CapacityReport
+		}
+	}
+	return nil
+}
+
+func (x *CTRLMessage) GetTraceChunk() *FileChunk {
+	if x != nil {
+		if x, ok := x.Payload.(*CTRLMessage_TraceChunk); ok {
+			return x.TraceChunk
+		}
+	}
+	return nil
+}
+
 type isCTRLMessage_Payload interface {
 	isCTRLMessage_Payload()
 }
@@ -140,6 +200,31 @@ type CTRLMessage_BenchmarkFinished struct {
 	BenchmarkFinished *BenchmarkFinished `protobuf:"bytes,6,opt,name=benchmark_finished,json=benchmarkFinished,proto3,oneof"`
 }
 
+type CTRLMessage_Prepare struct {
+	Prepare *Prepare `protobuf:"bytes,7,opt,name=prepare,proto3,oneof"`
+}
+
+type CTRLMessage_PrepareDone struct {
+	PrepareDone *PrepareDone `protobuf:"bytes,8,opt,name=prepare_done,json=prepareDone,proto3,oneof"`
+}
+
+type CTRLMessage_StartAt struct {
+	StartAt *StartAt `protobuf:"bytes,9,opt,name=start_at,json=startAt,proto3,oneof"`
+}
+
+type CTRLMessage_StepReport struct {
+	StepReport *StepReport `protobuf:"bytes,10,opt,name=step_report,json=stepReport,proto3,oneof"`
+}
+
+type CTRLMessage_CapacityReport struct {
+	CapacityReport *CapacityReport `protobuf:"bytes,11,opt,name=capacity_report,json=capacityReport,proto3,oneof"`
+}
+
+type CTRLMessage_TraceChunk struct {
+	// Trace of the replay scenario, sent in chunks before the config file
+	TraceChunk *FileChunk `protobuf:"bytes,12,opt,name=trace_chunk,json=traceChunk,proto3,oneof"`
+}
+
 func (*CTRLMessage_BenchmarkStatus) isCTRLMessage_Payload() {}
 
 func (*CTRLMessage_ConfigFile) isCTRLMessage_Payload() {}
@@ -150,11 +235,31 @@ func (*CTRLMessage_Shutdown) isCTRLMessage_Payload() {}
 
 func (*CTRLMessage_BenchmarkFinished) isCTRLMessage_Payload() {}
 
+func (*CTRLMessage_Prepare) isCTRLMessage_Payload() {}
+
+func (*CTRLMessage_PrepareDone) isCTRLMessage_Payload() {}
+
+func (*CTRLMessage_StartAt) isCTRLMessage_Payload() {}
+
+func (*CTRLMessage_StepReport) isCTRLMessage_Payload() {}
+
+func (*CTRLMessage_CapacityReport) isCTRLMessage_Payload() {}
+
+func (*CTRLMessage_TraceChunk) isCTRLMessage_Payload() {}
+
 type ConfigFile struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Content       []byte                 `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Content []byte                 `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+	// Position of the receiving benchmark client among all clients of the run
+	ClientIndex int32 `protobuf:"varint,2,opt,name=client_index,json=clientIndex,proto3" json:"client_index,omitempty"`
+	// Total number of benchmark clients taking part in the run
+	NumBenchClients int32 `protobuf:"varint,3,opt,name=num_bench_clients,json=numBenchClients,proto3" json:"num_bench_clients,omitempty"`
+	// Offset added to the seed of the per-goroutine random generators
+	SeedOffset int64 `protobuf:"varint,4,opt,name=seed_offset,json=seedOffset,proto3" json:"seed_offset,omitempty"`
+	// Offset added to the goroutine IDs reported in the metrics
+	ClientIdOffset int32 `protobuf:"varint,5,opt,name=client_id_offset,json=clientIdOffset,proto3" json:"client_id_offset,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *ConfigFile) Reset() {
@@ -194,6 +299,34 @@ func (x *ConfigFile) GetContent() []byte {
 	return nil
 }
 
+func (x *ConfigFile) GetClientIndex() int32 {
+	if x != nil {
+		return x.ClientIndex
+	}
+	return 0
+}
+
+func (x *ConfigFile) GetNumBenchClients() int32 {
+	if x != nil {
+		return x.NumBenchClients
+	}
+	return 0
+}
+
+func (x *ConfigFile) GetSeedOffset() int64 {
+	if x != nil {
+		return x.SeedOffset
+	}
+	return 0
+}
+
+func (x *ConfigFile) GetClientIdOffset() int32 {
+	if x != nil {
+		return x.ClientIdOffset
+	}
+	return 0
+}
+
 type ConfigFileResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
@@ -354,12 +487,697 @@ func (*BenchmarkFinished) Descriptor() ([]byte, []int) {
 	return file_benchmarkpb_benchmark_proto_rawDescGZIP(), []int{5}
 }
 
+// Prepare asks the benchmark client to generate and load the data
+type Prepare struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Prepare) Reset() {
+	*x = Prepare{}
+	mi := &file_benchmarkpb_benchmark_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Prepare) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Prepare) ProtoMessage() {}
+
+func (x *Prepare) ProtoReflect() protoreflect.Message {
+	mi := &file_benchmarkpb_benchmark_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Prepare.ProtoReflect.Descriptor instead.
+func (*Prepare) Descriptor() ([]byte, []int) {
+	return file_benchmarkpb_benchmark_proto_rawDescGZIP(), []int{6}
+}
+
+// PrepareDone reports that the benchmark client is ready to start the benchmark
+type PrepareDone struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NumKeys       int64                  `protobuf:"varint,1,opt,name=num_keys,json=numKeys,proto3" json:"num_keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PrepareDone) Reset() {
+	*x = PrepareDone{}
+	mi := &file_benchmarkpb_benchmark_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PrepareDone) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrepareDone) ProtoMessage() {}
+
+func (x *PrepareDone) ProtoReflect() protoreflect.Message {
+	mi := &file_benchmarkpb_benchmark_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrepareDone.ProtoReflect.Descriptor instead.
+func (*PrepareDone) Descriptor() ([]byte, []int) {
+	return file_benchmarkpb_benchmark_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PrepareDone) GetNumKeys() int64 {
+	if x != nil {
+		return x.NumKeys
+	}
+	return 0
+}
+
+// StartAt tells the benchmark client the wall-clock instant at which the
+// warm-up begins, all later load steps are aligned to this instant
+type StartAt struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UnixNano      int64                  `protobuf:"varint,1,opt,name=unix_nano,json=unixNano,proto3" json:"unix_nano,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartAt) Reset() {
+	*x = StartAt{}
+	mi := &file_benchmarkpb_benchmark_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartAt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartAt) ProtoMessage() {}
+
+func (x *StartAt) ProtoReflect() protoreflect.Message {
+	mi := &file_benchmarkpb_benchmark_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartAt.ProtoReflect.Descriptor instead.
+func (*StartAt) Descriptor() ([]byte, []int) {
+	return file_benchmarkpb_benchmark_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *StartAt) GetUnixNano() int64 {
+	if x != nil {
+		return x.UnixNano
+	}
+	return 0
+}
+
+// StepReport summarizes the results of a single load step, the warm-up is
+// reported as step 0 and the main steps are numbered from 1
+type StepReport struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StepIndex     int32                  `protobuf:"varint,1,opt,name=step_index,json=stepIndex,proto3" json:"step_index,omitempty"`
+	Phase         string                 `protobuf:"bytes,2,opt,name=phase,proto3" json:"phase,omitempty"`
+	NumClients    int32                  `protobuf:"varint,3,opt,name=num_clients,json=numClients,proto3" json:"num_clients,omitempty"`
+	StartUnixNano int64                  `protobuf:"varint,4,opt,name=start_unix_nano,json=startUnixNano,proto3" json:"start_unix_nano,omitempty"`
+	EndUnixNano   int64                  `protobuf:"varint,5,opt,name=end_unix_nano,json=endUnixNano,proto3" json:"end_unix_nano,omitempty"`
+	Operations    int64                  `protobuf:"varint,6,opt,name=operations,proto3" json:"operations,omitempty"`
+	Errors        int64                  `protobuf:"varint,7,opt,name=errors,proto3" json:"errors,omitempty"`
+	// Operations per second over the duration of the step
+	Throughput    float64 `protobuf:"fixed64,8,opt,name=throughput,proto3" json:"throughput,omitempty"`
+	LatencyP50Us  int64   `protobuf:"varint,9,opt,name=latency_p50_us,json=latencyP50Us,proto3" json:"latency_p50_us,omitempty"`
+	LatencyP90Us  int64   `protobuf:"varint,10,opt,name=latency_p90_us,json=latencyP90Us,proto3" json:"latency_p90_us,omitempty"`
+	LatencyP99Us  int64   `protobuf:"varint,11,opt,name=latency_p99_us,json=latencyP99Us,proto3" json:"latency_p99_us,omitempty"`
+	LatencyP999Us int64   `protobuf:"varint,12,opt,name=latency_p999_us,json=latencyP999Us,proto3" json:"latency_p999_us,omitempty"`
+	LatencyMaxUs  int64   `protobuf:"varint,13,opt,name=latency_max_us,json=latencyMaxUs,proto3" json:"latency_max_us,omitempty"`
+	// Number of failed operations by status code
+	ErrorCounts map[int32]int64 `protobuf:"bytes,14,rep,name=error_counts,json=errorCounts,proto3" json:"error_counts,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	// Target operations per second in open-loop mode, 0 in closed-loop mode
+	TargetRate    int64 `protobuf:"varint,15,opt,name=target_rate,json=targetRate,proto3" json:"target_rate,omitempty"`
+	LatencyMeanUs int64 `protobuf:"varint,16,opt,name=latency_mean_us,json=latencyMeanUs,proto3" json:"latency_mean_us,omitempty"`
+	// Standard deviation of the latencies
+	LatencyStddevUs int64 `protobuf:"varint,17,opt,name=latency_stddev_us,json=latencyStddevUs,proto3" json:"latency_stddev_us,omitempty"`
+	// Results of the step by operation, e.g. reads and writes
+	OperationReports []*OperationReport `protobuf:"bytes,18,rep,name=operation_reports,json=operationReports,proto3" json:"operation_reports,omitempty"`
+	// Size of the keys and values written and of the responses of the
+	// successful operations
+	Bytes int64 `protobuf:"varint,19,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	// Megabytes (10^6 bytes) per second over the duration of the step
+	Bandwidth float64 `protobuf:"fixed64,20,opt,name=bandwidth,proto3" json:"bandwidth,omitempty"`
+	// Events per second received by every watcher of the watch scenario,
+	// including the events of the writes of other benchmark clients
+	EventsPerWatcher float64 `protobuf:"fixed64,21,opt,name=events_per_watcher,json=eventsPerWatcher,proto3" json:"events_per_watcher,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *StepReport) Reset() {
+	*x = StepReport{}
+	mi := &file_benchmarkpb_benchmark_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StepReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StepReport) ProtoMessage() {}
+
+func (x *StepReport) ProtoReflect() protoreflect.Message {
+	mi := &file_benchmarkpb_benchmark_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StepReport.ProtoReflect.Descriptor instead.
+func (*StepReport) Descriptor() ([]byte, []int) {
+	return file_benchmarkpb_benchmark_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *StepReport) GetStepIndex() int32 {
+	if x != nil {
+		return x.StepIndex
+	}
+	return 0
+}
+
+func (x *StepReport) GetPhase() string {
+	if x != nil {
+		return x.Phase
+	}
+	return ""
+}
+
+func (x *StepReport) GetNumClients() int32 {
+	if x != nil {
+		return x.NumClients
+	}
+	return 0
+}
+
+func (x *StepReport) GetStartUnixNano() int64 {
+	if x != nil {
+		return x.StartUnixNano
+	}
+	return 0
+}
+
+func (x *StepReport) GetEndUnixNano() int64 {
+	if x != nil {
+		return x.EndUnixNano
+	}
+	return 0
+}
+
+func (x *StepReport) GetOperations() int64 {
+	if x != nil {
+		return x.Operations
+	}
+	return 0
+}
+
+func (x *StepReport) GetErrors() int64 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+func (x *StepReport) GetThroughput() float64 {
+	if x != nil {
+		return x.Throughput
+	}
+	return 0
+}
+
+func (x *StepReport) GetLatencyP50Us() int64 {
+	if x != nil {
+		return x.LatencyP50Us
+	}
+	return 0
+}
+
+func (x *StepReport) GetLatencyP90Us() int64 {
+	if x != nil {
+		return x.LatencyP90Us
+	}
+	return 0
+}
+
+func (x *StepReport) GetLatencyP99Us() int64 {
+	if x != nil {
+		return x.LatencyP99Us
+	}
+	return 0
+}
+
+func (x *StepReport) GetLatencyP999Us() int64 {
+	if x != nil {
+		return x.LatencyP999Us
+	}
+	return 0
+}
+
+func (x *StepReport) GetLatencyMaxUs() int64 {
+	if x != nil {
+		return x.LatencyMaxUs
+	}
+	return 0
+}
+
+func (x *StepReport) GetErrorCounts() map[int32]int64 {
+	if x != nil {
+		return x.ErrorCounts
+	}
+	return nil
+}
+
+func (x *StepReport) GetTargetRate() int64 {
+	if x != nil {
+		return x.TargetRate
+	}
+	return 0
+}
+
+func (x *StepReport) GetLatencyMeanUs() int64 {
+	if x != nil {
+		return x.LatencyMeanUs
+	}
+	return 0
+}
+
+func (x *StepReport) GetLatencyStddevUs() int64 {
+	if x != nil {
+		return x.LatencyStddevUs
+	}
+	return 0
+}
+
+func (x *StepReport) GetOperationReports() []*OperationReport {
+	if x != nil {
+		return x.OperationReports
+	}
+	return nil
+}
+
+func (x *StepReport) GetBytes() int64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *StepReport) GetBandwidth() float64 {
+	if x != nil {
+		return x.Bandwidth
+	}
+	return 0
+}
+
+func (x *StepReport) GetEventsPerWatcher() float64 {
+	if x != nil {
+		return x.EventsPerWatcher
+	}
+	return 0
+}
+
+// OperationReport holds the results of the operations of a step with the
+// same label, the operation name and the read consistency of reads
+type OperationReport struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Operation  string                 `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
+	Operations int64                  `protobuf:"varint,2,opt,name=operations,proto3" json:"operations,omitempty"`
+	Errors     int64                  `protobuf:"varint,3,opt,name=errors,proto3" json:"errors,omitempty"`
+	// Operations per second over the duration of the step
+	Throughput    float64 `protobuf:"fixed64,4,opt,name=throughput,proto3" json:"throughput,omitempty"`
+	LatencyMeanUs int64   `protobuf:"varint,5,opt,name=latency_mean_us,json=latencyMeanUs,proto3" json:"latency_mean_us,omitempty"`
+	LatencyP50Us  int64   `protobuf:"varint,6,opt,name=latency_p50_us,json=latencyP50Us,proto3" json:"latency_p50_us,omitempty"`
+	LatencyP90Us  int64   `protobuf:"varint,7,opt,name=latency_p90_us,json=latencyP90Us,proto3" json:"latency_p90_us,omitempty"`
+	LatencyP99Us  int64   `protobuf:"varint,8,opt,name=latency_p99_us,json=latencyP99Us,proto3" json:"latency_p99_us,omitempty"`
+	LatencyMaxUs  int64   `protobuf:"varint,9,opt,name=latency_max_us,json=latencyMaxUs,proto3" json:"latency_max_us,omitempty"`
+	Bytes         int64   `protobuf:"varint,10,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	// Megabytes (10^6 bytes) per second over the duration of the step
+	Bandwidth     float64 `protobuf:"fixed64,11,opt,name=bandwidth,proto3" json:"bandwidth,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OperationReport) Reset() {
+	*x = OperationReport{}
+	mi := &file_benchmarkpb_benchmark_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OperationReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OperationReport) ProtoMessage() {}
+
+func (x *OperationReport) ProtoReflect() protoreflect.Message {
+	mi := &file_benchmarkpb_benchmark_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OperationReport.ProtoReflect.Descriptor instead.
+func (*OperationReport) Descriptor() ([]byte, []int) {
+	return file_benchmarkpb_benchmark_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *OperationReport) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *OperationReport) GetOperations() int64 {
+	if x != nil {
+		return x.Operations
+	}
+	return 0
+}
+
+func (x *OperationReport) GetErrors() int64 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+func (x *OperationReport) GetThroughput() float64 {
+	if x != nil {
+		return x.Throughput
+	}
+	return 0
+}
+
+func (x *OperationReport) GetLatencyMeanUs() int64 {
+	if x != nil {
+		return x.LatencyMeanUs
+	}
+	return 0
+}
+
+func (x *OperationReport) GetLatencyP50Us() int64 {
+	if x != nil {
+		return x.LatencyP50Us
+	}
+	return 0
+}
+
+func (x *OperationReport) GetLatencyP90Us() int64 {
+	if x != nil {
+		return x.LatencyP90Us
+	}
+	return 0
+}
+
+func (x *OperationReport) GetLatencyP99Us() int64 {
+	if x != nil {
+		return x.LatencyP99Us
+	}
+	return 0
+}
+
+func (x *OperationReport) GetLatencyMaxUs() int64 {
+	if x != nil {
+		return x.LatencyMaxUs
+	}
+	return 0
+}
+
+func (x *OperationReport) GetBytes() int64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *OperationReport) GetBandwidth() float64 {
+	if x != nil {
+		return x.Bandwidth
+	}
+	return 0
+}
+
+// CapacityReport is the outcome of the SLA-driven capacity search
+type CapacityReport struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether any step stayed within the SLA
+	Found bool `protobuf:"varint,1,opt,name=found,proto3" json:"found,omitempty"`
+	// Whether the search narrowed down the capacity within the total duration
+	Converged     bool    `protobuf:"varint,2,opt,name=converged,proto3" json:"converged,omitempty"`
+	SlaPercentile float64 `protobuf:"fixed64,3,opt,name=sla_percentile,json=slaPercentile,proto3" json:"sla_percentile,omitempty"`
+	SlaLatencyUs  int64   `protobuf:"varint,4,opt,name=sla_latency_us,json=slaLatencyUs,proto3" json:"sla_latency_us,omitempty"`
+	// Passing step with the highest throughput, unset if none was found
+	Knee *StepReport `protobuf:"bytes,5,opt,name=knee,proto3" json:"knee,omitempty"`
+	// Latency at the SLA percentile in the knee step
+	LatencyUs     int64 `protobuf:"varint,6,opt,name=latency_us,json=latencyUs,proto3" json:"latency_us,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CapacityReport) Reset() {
+	*x = CapacityReport{}
+	mi := &file_benchmarkpb_benchmark_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CapacityReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CapacityReport) ProtoMessage() {}
+
+func (x *CapacityReport) ProtoReflect() protoreflect.Message {
+	mi := &file_benchmarkpb_benchmark_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CapacityReport.ProtoReflect.Descriptor instead.
+func (*CapacityReport) Descriptor() ([]byte, []int) {
+	return file_benchmarkpb_benchmark_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CapacityReport) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *CapacityReport) GetConverged() bool {
+	if x != nil {
+		return x.Converged
+	}
+	return false
+}
+
+func (x *CapacityReport) GetSlaPercentile() float64 {
+	if x != nil {
+		return x.SlaPercentile
+	}
+	return 0
+}
+
+func (x *CapacityReport) GetSlaLatencyUs() int64 {
+	if x != nil {
+		return x.SlaLatencyUs
+	}
+	return 0
+}
+
+func (x *CapacityReport) GetKnee() *StepReport {
+	if x != nil {
+		return x.Knee
+	}
+	return nil
+}
+
+func (x *CapacityReport) GetLatencyUs() int64 {
+	if x != nil {
+		return x.LatencyUs
+	}
+	return 0
+}
+
+// PullResultsRequest asks the benchmark client for its result files
+type PullResultsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Names of the files to transfer, all result files when empty
+	Files         []string `protobuf:"bytes,1,rep,name=files,proto3" json:"files,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PullResultsRequest) Reset() {
+	*x = PullResultsRequest{}
+	mi := &file_benchmarkpb_benchmark_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PullResultsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PullResultsRequest) ProtoMessage() {}
+
+func (x *PullResultsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_benchmarkpb_benchmark_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PullResultsRequest.ProtoReflect.Descriptor instead.
+func (*PullResultsRequest) Descriptor() ([]byte, []int) {
+	return file_benchmarkpb_benchmark_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *PullResultsRequest) GetFiles() []string {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+// FileChunk is a piece of a transferred file, the last chunk of every file
+// carries the hex encoded SHA-256 checksum of the whole file
+type FileChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Offset        int64                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Data          []byte                 `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	Last          bool                   `protobuf:"varint,4,opt,name=last,proto3" json:"last,omitempty"`
+	Sha256        string                 `protobuf:"bytes,5,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileChunk) Reset() {
+	*x = FileChunk{}
+	mi := &file_benchmarkpb_benchmark_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileChunk) ProtoMessage() {}
+
+func (x *FileChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_benchmarkpb_benchmark_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileChunk.ProtoReflect.Descriptor instead.
+func (*FileChunk) Descriptor() ([]byte, []int) {
+	return file_benchmarkpb_benchmark_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *FileChunk) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FileChunk) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *FileChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *FileChunk) GetLast() bool {
+	if x != nil {
+		return x.Last
+	}
+	return false
+}
+
+func (x *FileChunk) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
 var File_benchmarkpb_benchmark_proto protoreflect.FileDescriptor
 
 var file_benchmarkpb_benchmark_proto_rawDesc = []byte{
 	0x0a, 0x1b, 0x62, 0x65, 0x6e, 0x63, 0x68, 0x6d, 0x61, 0x72, 0x6b, 0x70, 0x62, 0x2f, 0x62, 0x65,
 	0x6e, 0x63, 0x68, 0x6d, 0x61, 0x72, 0x6b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0b, 0x62,
-	0x65, 0x6e, 0x63, 0x68, 0x6d, 0x61, 0x72, 0x6b, 0x70, 0x62, 0x22, 0xfa, 0x02, 0x0a, 0x0b, 0x43,
+	0x65, 0x6e, 0x63, 0x68, 0x6d, 0x61, 0x72, 0x6b, 0x70, 0x62, 0x22, 0xdd, 0x05, 0x0a, 0x0b, 0x43,
 	0x54, 0x52, 0x4c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x49, 0x0a, 0x10, 0x62, 0x65,
 	0x6e, 0x63, 0x68, 0x6d, 0x61, 0x72, 0x6b, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01,
 	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x62, 0x65, 0x6e, 0x63, 0x68, 0x6d, 0x61, 0x72, 0x6b,
@@ -382,26 +1200,172 @@ var file_benchmarkpb_benchmark_proto_rawDesc = []byte{
 	0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x62, 0x65, 0x6e, 0x63, 0x68, 0x6d,
 	0x61, 0x72, 0x6b, 0x70, 0x62, 0x2e, 0x42, 0x65, 0x6e, 0x63, 0x68, 0x6d, 0x61, 0x72, 0x6b, 0x46,
 	0x69, 0x6e, 0x69, 0x73, 0x68, 0x65, 0x64, 0x48, 0x00, 0x52, 0x11, 0x62, 0x65, 0x6e, 0x63, 0x68,
-	0x6d, 0x61, 0x72, 0x6b, 0x46, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x65, 0x64, 0x42, 0x09, 0x0a, 0x07,
-	0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0x26, 0x0a, 0x0a, 0x43, 0x6f, 0x6e, 0x66, 0x69,
-	0x67, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22,
-	0x2e, 0x0a, 0x12, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x22,
-	0x29, 0x0a, 0x0f, 0x42, 0x65, 0x6e, 0x63, 0x68, 0x6d, 0x61, 0x72, 0x6b, 0x53, 0x74, 0x61, 0x74,
-	0x75, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x0a, 0x0a, 0x08, 0x53, 0x68,
-	0x75, 0x74, 0x64, 0x6f, 0x77, 0x6e, 0x22, 0x13, 0x0a, 0x11, 0x42, 0x65, 0x6e, 0x63, 0x68, 0x6d,
-	0x61, 0x72, 0x6b, 0x46, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x65, 0x64, 0x32, 0x5a, 0x0a, 0x10, 0x42,
-	0x65, 0x6e, 0x63, 0x68, 0x6d, 0x61, 0x72, 0x6b, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12,
-	0x46, 0x0a, 0x0a, 0x43, 0x54, 0x52, 0x4c, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x18, 0x2e,
-	0x62, 0x65, 0x6e, 0x63, 0x68, 0x6d, 0x61, 0x72, 0x6b, 0x70, 0x62, 0x2e, 0x43, 0x54, 0x52, 0x4c,
-	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x18, 0x2e, 0x62, 0x65, 0x6e, 0x63, 0x68, 0x6d,
-	0x61, 0x72, 0x6b, 0x70, 0x62, 0x2e, 0x43, 0x54, 0x52, 0x4c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x42, 0x15, 0x5a, 0x13, 0x63, 0x73, 0x62, 0x2f, 0x61,
-	0x70, 0x69, 0x2f, 0x62, 0x65, 0x6e, 0x63, 0x68, 0x6d, 0x61, 0x72, 0x6b, 0x70, 0x62, 0x62, 0x06,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6d, 0x61, 0x72, 0x6b, 0x46, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x65, 0x64, 0x12, 0x30, 0x0a, 0x07,
+	0x70, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e,
+	0x62, 0x65, 0x6e, 0x63, 0x68, 0x6d, 0x61, 0x72, 0x6b, 0x70, 0x62, 0x2e, 0x50, 0x72, 0x65, 0x70,
+	0x61, 0x72, 0x65, 0x48, 0x00, 0x52, 0x07, 0x70, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x12, 0x3d,
+	0x0a, 0x0c, 0x70, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x5f, 0x64, 0x6f, 0x6e, 0x65, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x62, 0x65, 0x6e, 0x63, 0x68, 0x6d, 0x61, 0x72, 0x6b,
+	0x70, 0x62, 0x2e, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x44, 0x6f, 0x6e, 0x65, 0x48, 0x00,
+	0x52, 0x0b, 0x70, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x44, 0x6f, 0x6e, 0x65, 0x12, 0x31, 0x0a,
+	0x08, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x61, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x14, 0x2e, 0x62, 0x65, 0x6e, 0x63, 0x68, 0x6d, 0x61, 0x72, 0x6b, 0x70, 0x62, 0x2e, 0x53, 0x74,
+	0x61, 0x72, 0x74, 0x41, 0x74, 0x48, 0x00, 0x52, 0x07, 0x73, 0x74, 0x61, 0x72, 0x74, 0x41, 0x74,
+	0x12, 0x3a, 0x0a, 0x0b, 0x73, 0x74, 0x65, 0x70, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x62, 0x65, 0x6e, 0x63, 0x68, 0x6d, 0x61, 0x72,
+	0x6b, 0x70, 0x62, 0x2e, 0x53, 0x74, 0x65, 0x70, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x48, 0x00,
+	0x52, 0x0a, 0x73, 0x74, 0x65, 0x70, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x46, 0x0a, 0x0f,
+	0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x18,
+	0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x62, 0x65, 0x6e, 0x63, 0x68, 0x6d, 0x61, 0x72,
+	0x6b, 0x70, 0x62, 0x2e, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x52, 0x65, 0x70, 0x6f,
+	0x72, 0x74, 0x48, 0x00, 0x52, 0x0e, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x52, 0x65,
+	0x70, 0x6f, 0x72, 0x74, 0x12, 0x39, 0x0a, 0x0b, 0x74, 0x72, 0x61, 0x63, 0x65, 0x5f, 0x63, 0x68,
+	0x75, 0x6e, 0x6b, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x62, 0x65, 0x6e, 0x63,
+	0x68, 0x6d, 0x61, 0x72, 0x6b, 0x70, 0x62, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x68, 0x75, 0x6e,
+	0x6b, 0x48, 0x00, 0x52, 0x0a, 0x74, 0x72, 0x61, 0x63, 0x65, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x42,
+	0x09, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0xc0, 0x01, 0x0a, 0x0a, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e,
+	0x74, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x6e,
+	0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x63, 0x6c, 0x69, 0x65, 0x6e,
+	0x74, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x2a, 0x0a, 0x11, 0x6e, 0x75, 0x6d, 0x5f, 0x62, 0x65,
+	0x6e, 0x63, 0x68, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x0f, 0x6e, 0x75, 0x6d, 0x42, 0x65, 0x6e, 0x63, 0x68, 0x43, 0x6c, 0x69, 0x65, 0x6e,
+	0x74, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x65, 0x65, 0x64, 0x5f, 0x6f, 0x66, 0x66, 0x73, 0x65,
+	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x73, 0x65, 0x65, 0x64, 0x4f, 0x66, 0x66,
+	0x73, 0x65, 0x74, 0x12, 0x28, 0x0a, 0x10, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64,
+	0x5f, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x63,
+	0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x22, 0x2e, 0x0a,
+	0x12, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x22, 0x29, 0x0a,
+	0x0f, 0x42, 0x65, 0x6e, 0x63, 0x68, 0x6d, 0x61, 0x72, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x0a, 0x0a, 0x08, 0x53, 0x68, 0x75, 0x74,
+	0x64, 0x6f, 0x77, 0x6e, 0x22, 0x13, 0x0a, 0x11, 0x42, 0x65, 0x6e, 0x63, 0x68, 0x6d, 0x61, 0x72,
+	0x6b, 0x46, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x65, 0x64, 0x22, 0x09, 0x0a, 0x07, 0x50, 0x72, 0x65,
+	0x70, 0x61, 0x72, 0x65, 0x22, 0x28, 0x0a, 0x0b, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x44,
+	0x6f, 0x6e, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x6e, 0x75, 0x6d, 0x5f, 0x6b, 0x65, 0x79, 0x73, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x6e, 0x75, 0x6d, 0x4b, 0x65, 0x79, 0x73, 0x22, 0x26,
+	0x0a, 0x07, 0x53, 0x74, 0x61, 0x72, 0x74, 0x41, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x75, 0x6e, 0x69,
+	0x78, 0x5f, 0x6e, 0x61, 0x6e, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x75, 0x6e,
+	0x69, 0x78, 0x4e, 0x61, 0x6e, 0x6f, 0x22, 0xf5, 0x06, 0x0a, 0x0a, 0x53, 0x74, 0x65, 0x70, 0x52,
+	0x65, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x65, 0x70, 0x5f, 0x69, 0x6e,
+	0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x73, 0x74, 0x65, 0x70, 0x49,
+	0x6e, 0x64, 0x65, 0x78, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x6e, 0x75,
+	0x6d, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0a, 0x6e, 0x75, 0x6d, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x5f, 0x75, 0x6e, 0x69, 0x78, 0x5f, 0x6e, 0x61, 0x6e, 0x6f, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x73, 0x74, 0x61, 0x72, 0x74, 0x55, 0x6e, 0x69, 0x78, 0x4e,
+	0x61, 0x6e, 0x6f, 0x12, 0x22, 0x0a, 0x0d, 0x65, 0x6e, 0x64, 0x5f, 0x75, 0x6e, 0x69, 0x78, 0x5f,
+	0x6e, 0x61, 0x6e, 0x6f, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x65, 0x6e, 0x64, 0x55,
+	0x6e, 0x69, 0x78, 0x4e, 0x61, 0x6e, 0x6f, 0x12, 0x1e, 0x0a, 0x0a, 0x6f, 0x70, 0x65, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x6f, 0x70, 0x65,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x12,
+	0x1e, 0x0a, 0x0a, 0x74, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x70, 0x75, 0x74, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x0a, 0x74, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x70, 0x75, 0x74, 0x12,
+	0x24, 0x0a, 0x0e, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x70, 0x35, 0x30, 0x5f, 0x75,
+	0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79,
+	0x50, 0x35, 0x30, 0x55, 0x73, 0x12, 0x24, 0x0a, 0x0e, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79,
+	0x5f, 0x70, 0x39, 0x30, 0x5f, 0x75, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x6c,
+	0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x50, 0x39, 0x30, 0x55, 0x73, 0x12, 0x24, 0x0a, 0x0e, 0x6c,
+	0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x70, 0x39, 0x39, 0x5f, 0x75, 0x73, 0x18, 0x0b, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x0c, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x50, 0x39, 0x39, 0x55,
+	0x73, 0x12, 0x26, 0x0a, 0x0f, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x70, 0x39, 0x39,
+	0x39, 0x5f, 0x75, 0x73, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x6c, 0x61, 0x74, 0x65,
+	0x6e, 0x63, 0x79, 0x50, 0x39, 0x39, 0x39, 0x55, 0x73, 0x12, 0x24, 0x0a, 0x0e, 0x6c, 0x61, 0x74,
+	0x65, 0x6e, 0x63, 0x79, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x75, 0x73, 0x18, 0x0d, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0c, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x4d, 0x61, 0x78, 0x55, 0x73, 0x12,
+	0x4b, 0x0a, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x18,
+	0x0e, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x62, 0x65, 0x6e, 0x63, 0x68, 0x6d, 0x61, 0x72,
+	0x6b, 0x70, 0x62, 0x2e, 0x53, 0x74, 0x65, 0x70, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x2e, 0x45,
+	0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x0b, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x12, 0x1f, 0x0a, 0x0b,
+	0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x0f, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0a, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x61, 0x74, 0x65, 0x12, 0x26, 0x0a,
+	0x0f, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x6d, 0x65, 0x61, 0x6e, 0x5f, 0x75, 0x73,
+	0x18, 0x10, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x4d,
+	0x65, 0x61, 0x6e, 0x55, 0x73, 0x12, 0x2a, 0x0a, 0x11, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79,
+	0x5f, 0x73, 0x74, 0x64, 0x64, 0x65, 0x76, 0x5f, 0x75, 0x73, 0x18, 0x11, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0f, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x53, 0x74, 0x64, 0x64, 0x65, 0x76, 0x55,
+	0x73, 0x12, 0x49, 0x0a, 0x11, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x72,
+	0x65, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x18, 0x12, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x62,
+	0x65, 0x6e, 0x63, 0x68, 0x6d, 0x61, 0x72, 0x6b, 0x70, 0x62, 0x2e, 0x4f, 0x70, 0x65, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x10, 0x6f, 0x70, 0x65, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x12, 0x14, 0x0a, 0x05,
+	0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x13, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x62, 0x79, 0x74,
+	0x65, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x62, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x18,
+	0x14, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x62, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68,
+	0x12, 0x2c, 0x0a, 0x12, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x77,
+	0x61, 0x74, 0x63, 0x68, 0x65, 0x72, 0x18, 0x15, 0x20, 0x01, 0x28, 0x01, 0x52, 0x10, 0x65, 0x76,
+	0x65, 0x6e, 0x74, 0x73, 0x50, 0x65, 0x72, 0x57, 0x61, 0x74, 0x63, 0x68, 0x65, 0x72, 0x1a, 0x3e,
+	0x0a, 0x10, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xfb,
+	0x02, 0x0a, 0x0f, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6f,
+	0x72, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x1e, 0x0a, 0x0a, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x12, 0x16, 0x0a, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x74, 0x68, 0x72, 0x6f,
+	0x75, 0x67, 0x68, 0x70, 0x75, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x74, 0x68,
+	0x72, 0x6f, 0x75, 0x67, 0x68, 0x70, 0x75, 0x74, 0x12, 0x26, 0x0a, 0x0f, 0x6c, 0x61, 0x74, 0x65,
+	0x6e, 0x63, 0x79, 0x5f, 0x6d, 0x65, 0x61, 0x6e, 0x5f, 0x75, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0d, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x4d, 0x65, 0x61, 0x6e, 0x55, 0x73,
+	0x12, 0x24, 0x0a, 0x0e, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x70, 0x35, 0x30, 0x5f,
+	0x75, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63,
+	0x79, 0x50, 0x35, 0x30, 0x55, 0x73, 0x12, 0x24, 0x0a, 0x0e, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63,
+	0x79, 0x5f, 0x70, 0x39, 0x30, 0x5f, 0x75, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c,
+	0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x50, 0x39, 0x30, 0x55, 0x73, 0x12, 0x24, 0x0a, 0x0e,
+	0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x70, 0x39, 0x39, 0x5f, 0x75, 0x73, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x50, 0x39, 0x39,
+	0x55, 0x73, 0x12, 0x24, 0x0a, 0x0e, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x6d, 0x61,
+	0x78, 0x5f, 0x75, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x6c, 0x61, 0x74, 0x65,
+	0x6e, 0x63, 0x79, 0x4d, 0x61, 0x78, 0x55, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x79, 0x74, 0x65,
+	0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x62, 0x79, 0x74, 0x65, 0x73, 0x12, 0x1c,
+	0x0a, 0x09, 0x62, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x18, 0x0b, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x09, 0x62, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x22, 0xdd, 0x01, 0x0a,
+	0x0e, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x12,
+	0x14, 0x0a, 0x05, 0x66, 0x6f, 0x75, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05,
+	0x66, 0x6f, 0x75, 0x6e, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x67,
+	0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x63, 0x6f, 0x6e, 0x76, 0x65, 0x72,
+	0x67, 0x65, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x6c, 0x61, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65,
+	0x6e, 0x74, 0x69, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0d, 0x73, 0x6c, 0x61,
+	0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x69, 0x6c, 0x65, 0x12, 0x24, 0x0a, 0x0e, 0x73, 0x6c,
+	0x61, 0x5f, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0c, 0x73, 0x6c, 0x61, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x55, 0x73,
+	0x12, 0x2b, 0x0a, 0x04, 0x6b, 0x6e, 0x65, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17,
+	0x2e, 0x62, 0x65, 0x6e, 0x63, 0x68, 0x6d, 0x61, 0x72, 0x6b, 0x70, 0x62, 0x2e, 0x53, 0x74, 0x65,
+	0x70, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x04, 0x6b, 0x6e, 0x65, 0x65, 0x12, 0x1d, 0x0a,
+	0x0a, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x75, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x09, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x55, 0x73, 0x22, 0x2a, 0x0a, 0x12,
+	0x50, 0x75, 0x6c, 0x6c, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x22, 0x77, 0x0a, 0x09, 0x46, 0x69, 0x6c, 0x65,
+	0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66,
+	0x73, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x61, 0x73, 0x74, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x04, 0x6c, 0x61, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x68, 0x61,
+	0x32, 0x35, 0x36, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x68, 0x61, 0x32, 0x35,
+	0x36, 0x32, 0xa6, 0x01, 0x0a, 0x10, 0x42, 0x65, 0x6e, 0x63, 0x68, 0x6d, 0x61, 0x72, 0x6b, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x46, 0x0a, 0x0a, 0x43, 0x54, 0x52, 0x4c, 0x53, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x12, 0x18, 0x2e, 0x62, 0x65, 0x6e, 0x63, 0x68, 0x6d, 0x61, 0x72, 0x6b,
+	0x70, 0x62, 0x2e, 0x43, 0x54, 0x52, 0x4c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x18,
+	0x2e, 0x62, 0x65, 0x6e, 0x63, 0x68, 0x6d, 0x61, 0x72, 0x6b, 0x70, 0x62, 0x2e, 0x43, 0x54, 0x52,
+	0x4c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x12, 0x4a,
+	0x0a, 0x0b, 0x50, 0x75, 0x6c, 0x6c, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x1f, 0x2e,
+	0x62, 0x65, 0x6e, 0x63, 0x68, 0x6d, 0x61, 0x72, 0x6b, 0x70, 0x62, 0x2e, 0x50, 0x75, 0x6c, 0x6c,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16,
+	0x2e, 0x62, 0x65, 0x6e, 0x63, 0x68, 0x6d, 0x61, 0x72, 0x6b, 0x70, 0x62, 0x2e, 0x46, 0x69, 0x6c,
+	0x65, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x22, 0x00, 0x30, 0x01, 0x42, 0x15, 0x5a, 0x13, 0x63, 0x73,
+	0x62, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x62, 0x65, 0x6e, 0x63, 0x68, 0x6d, 0x61, 0x72, 0x6b, 0x70,
+	0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -416,7 +1380,7 @@ func file_benchmarkpb_benchmark_proto_rawDescGZIP() []byte {
 	return file_benchmarkpb_benchmark_proto_rawDescData
 }
 
-var file_benchmarkpb_benchmark_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_benchmarkpb_benchmark_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
 var file_benchmarkpb_benchmark_proto_goTypes = []any{
 	(*CTRLMessage)(nil),        // 0: benchmarkpb.CTRLMessage
 	(*ConfigFile)(nil),         // 1: benchmarkpb.ConfigFile
@@ -424,20 +1388,40 @@ var file_benchmarkpb_benchmark_proto_goTypes = []any{
 	(*BenchmarkStatus)(nil),    // 3: benchmarkpb.BenchmarkStatus
 	(*Shutdown)(nil),           // 4: benchmarkpb.Shutdown
 	(*BenchmarkFinished)(nil),  // 5: benchmarkpb.BenchmarkFinished
+	(*Prepare)(nil),            // 6: benchmarkpb.Prepare
+	(*PrepareDone)(nil),        // 7: benchmarkpb.PrepareDone
+	(*StartAt)(nil),            // 8: benchmarkpb.StartAt
+	(*StepReport)(nil),         // 9: benchmarkpb.StepReport
+	(*OperationReport)(nil),    // 10: benchmarkpb.OperationReport
+	(*CapacityReport)(nil),     // 11: benchmarkpb.CapacityReport
+	(*PullResultsRequest)(nil), // 12: benchmarkpb.PullResultsRequest
+	(*FileChunk)(nil),          // 13: benchmarkpb.FileChunk
+	nil,                        // 14: benchmarkpb.StepReport.ErrorCountsEntry
 }
 var file_benchmarkpb_benchmark_proto_depIdxs = []int32{
-	3, // 0: benchmarkpb.CTRLMessage.benchmark_status:type_name -> benchmarkpb.BenchmarkStatus
-	1, // 1: benchmarkpb.CTRLMessage.config_file:type_name -> benchmarkpb.ConfigFile
-	2, // 2: benchmarkpb.CTRLMessage.config_file_response:type_name -> benchmarkpb.ConfigFileResponse
-	4, // 3: benchmarkpb.CTRLMessage.shutdown:type_name -> benchmarkpb.Shutdown
-	5, // 4: benchmarkpb.CTRLMessage.benchmark_finished:type_name -> benchmarkpb.BenchmarkFinished
-	0, // 5: benchmarkpb.BenchmarkService.CTRLStream:input_type -> benchmarkpb.CTRLMessage
-	0, // 6: benchmarkpb.BenchmarkService.CTRLStream:output_type -> benchmarkpb.CTRLMessage
-	6, // [6:7] is the sub-list for method output_type
-	5, // [5:6] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	3,  // 0: benchmarkpb.CTRLMessage.benchmark_status:type_name -> benchmarkpb.BenchmarkStatus
+	1,  // 1: benchmarkpb.CTRLMessage.config_file:type_name -> benchmarkpb.ConfigFile
+	2,  // 2: benchmarkpb.CTRLMessage.config_file_response:type_name -> benchmarkpb.ConfigFileResponse
+	4,  // 3: benchmarkpb.CTRLMessage.shutdown:type_name -> benchmarkpb.Shutdown
+	5,  // 4: benchmarkpb.CTRLMessage.benchmark_finished:type_name -> benchmarkpb.BenchmarkFinished
+	6,  // 5: benchmarkpb.CTRLMessage.prepare:type_name -> benchmarkpb.Prepare
+	7,  // 6: benchmarkpb.CTRLMessage.prepare_done:type_name -> benchmarkpb.PrepareDone
+	8,  // 7: benchmarkpb.CTRLMessage.start_at:type_name -> benchmarkpb.StartAt
+	9,  // 8: benchmarkpb.CTRLMessage.step_report:type_name -> benchmarkpb.StepReport
+	11, // 9: benchmarkpb.CTRLMessage.capacity_report:type_name -> benchmarkpb.CapacityReport
+	13, // 10: benchmarkpb.CTRLMessage.trace_chunk:type_name -> benchmarkpb.FileChunk
+	14, // 11: benchmarkpb.StepReport.error_counts:type_name -> benchmarkpb.StepReport.ErrorCountsEntry
+	10, // 12: benchmarkpb.StepReport.operation_reports:type_name -> benchmarkpb.OperationReport
+	9,  // 13: benchmarkpb.CapacityReport.knee:type_name -> benchmarkpb.StepReport
+	0,  // 14: benchmarkpb.BenchmarkService.CTRLStream:input_type -> benchmarkpb.CTRLMessage
+	12, // 15: benchmarkpb.BenchmarkService.PullResults:input_type -> benchmarkpb.PullResultsRequest
+	0,  // 16: benchmarkpb.BenchmarkService.CTRLStream:output_type -> benchmarkpb.CTRLMessage
+	13, // 17: benchmarkpb.BenchmarkService.PullResults:output_type -> benchmarkpb.FileChunk
+	16, // [16:18] is the sub-list for method output_type
+	14, // [14:16] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
 }
 
 func init() { file_benchmarkpb_benchmark_proto_init() }
@@ -451,6 +1435,12 @@ func file_benchmarkpb_benchmark_proto_init() {
 		(*CTRLMessage_ConfigFileResponse)(nil),
 		(*CTRLMessage_Shutdown)(nil),
 		(*CTRLMessage_BenchmarkFinished)(nil),
+		(*CTRLMessage_Prepare)(nil),
+		(*CTRLMessage_PrepareDone)(nil),
+		(*CTRLMessage_StartAt)(nil),
+		(*CTRLMessage_StepReport)(nil),
+		(*CTRLMessage_CapacityReport)(nil),
+		(*CTRLMessage_TraceChunk)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -458,7 +1448,7 @@ func file_benchmarkpb_benchmark_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_benchmarkpb_benchmark_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   15,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,7 +19,8 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	BenchmarkService_CTRLStream_FullMethodName = "/benchmarkpb.BenchmarkService/CTRLStream"
+	BenchmarkService_CTRLStream_FullMethodName  = "/benchmarkpb.BenchmarkService/CTRLStream"
+	BenchmarkService_PullResults_FullMethodName = "/benchmarkpb.BenchmarkService/PullResults"
 )
 
 // BenchmarkServiceClient is the client API for BenchmarkService service.
@@ -29,6 +30,7 @@ const (
 // BenchmarkService defines the interface between control and client
 type BenchmarkServiceClient interface {
 	CTRLStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[CTRLMessage, CTRLMessage], error)
+	PullResults(ctx context.Context, in *PullResultsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FileChunk], error)
 }
 
 type benchmarkServiceClient struct {
@@ -52,6 +54,25 @@ func (c *benchmarkServiceClient) CTRLStream(ctx context.Context, opts ...grpc.Ca
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type BenchmarkService_CTRLStreamClient = grpc.BidiStreamingClient[CTRLMessage, CTRLMessage]
 
+func (c *benchmarkServiceClient) PullResults(ctx context.Context, in *PullResultsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FileChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BenchmarkService_ServiceDesc.Streams[1], BenchmarkService_PullResults_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[PullResultsRequest, FileChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BenchmarkService_PullResultsClient = grpc.ServerStreamingClient[FileChunk]
+
 // BenchmarkServiceServer is the server API for BenchmarkService service.
 // All implementations must embed UnimplementedBenchmarkServiceServer
 // for forward compatibility.
@@ -59,6 +80,7 @@ type BenchmarkService_CTRLStreamClient = grpc.BidiStreamingClient[CTRLMessage, C
 // BenchmarkService defines the interface between control and client
 type BenchmarkServiceServer interface {
 	CTRLStream(grpc.BidiStreamingServer[CTRLMessage, CTRLMessage]) error
+	PullResults(*PullResultsRequest, grpc.ServerStreamingServer[FileChunk]) error
 	mustEmbedUnimplementedBenchmarkServiceServer()
 }
 
@@ -72,6 +94,9 @@ type UnimplementedBenchmarkServiceServer struct{}
 func (UnimplementedBenchmarkServiceServer) CTRLStream(grpc.BidiStreamingServer[CTRLMessage, CTRLMessage]) error {
 	return status.Errorf(codes.Unimplemented, "method CTRLStream not implemented")
 }
+func (UnimplementedBenchmarkServiceServer) PullResults(*PullResultsRequest, grpc.ServerStreamingServer[FileChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method PullResults not implemented")
+}
 func (UnimplementedBenchmarkServiceServer) mustEmbedUnimplementedBenchmarkServiceServer() {}
 func (UnimplementedBenchmarkServiceServer) testEmbeddedByValue()                          {}
 
@@ -100,6 +125,17 @@ func _BenchmarkService_CTRLStream_Handler(srv interface{}, stream grpc.ServerStr
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type BenchmarkService_CTRLStreamServer = grpc.BidiStreamingServer[CTRLMessage, CTRLMessage]
 
+func _BenchmarkService_PullResults_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PullResultsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BenchmarkServiceServer).PullResults(m, &grpc.GenericServerStream[PullResultsRequest, FileChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BenchmarkService_PullResultsServer = grpc.ServerStreamingServer[FileChunk]
+
 // BenchmarkService_ServiceDesc is the grpc.ServiceDesc for BenchmarkService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -114,6 +150,11 @@ var BenchmarkService_ServiceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "PullResults",
+			Handler:       _BenchmarkService_PullResults_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "benchmarkpb/benchmark.proto",
 }
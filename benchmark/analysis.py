@@ -44,16 +44,57 @@ class EtcdPerfAnalyzer:
         }
     )
 
+    # metrics files of format version 2 start with a marker line and record
+    # the latencies in microseconds, older files record them in milliseconds
+    format_marker = "# csb-metrics format_version="
+
     def __init__(self, base_path: str):
         self.base_path = Path(base_path)
 
+    @staticmethod
+    def read_format_version(path: Path) -> int:
+        with open(path) as f:
+            first_line = f.readline().strip()
+        if first_line.startswith(EtcdPerfAnalyzer.format_marker):
+            return int(first_line[len(EtcdPerfAnalyzer.format_marker) :])
+        return 1
+
     def load_metrics(self, path: Path, scenario: str) -> pl.DataFrame:
-        df: pl.DataFrame = pl.read_csv(
-            path,
-            schema=EtcdPerfAnalyzer.lock_schema
+        schema = (
+            EtcdPerfAnalyzer.lock_schema
             if scenario == "lock"
-            else EtcdPerfAnalyzer.kv_schema,
+            else EtcdPerfAnalyzer.kv_schema
         )
+        version = self.read_format_version(path)
+        if version == 1:
+            df: pl.DataFrame = pl.read_csv(path, schema=schema)
+        else:
+            # read the latencies in microseconds and convert them to
+            # fractional milliseconds, the columns are matched by name since
+            # newer metrics files may have additional columns
+            dtypes = {
+                name.replace("_ms", "_us"): (
+                    pl.UInt64() if name.endswith("latency_ms") else dtype
+                )
+                for name, dtype in schema.items()
+            }
+            dtypes["operation"] = dtypes.pop("opeartion")
+            columns = pl.read_csv(path, skip_rows=1, n_rows=0).columns
+            us_columns = [name for name in columns if name.endswith("latency_us")]
+            df = (
+                pl.read_csv(
+                    path,
+                    skip_rows=1,
+                    schema_overrides={c: dtypes[c] for c in columns if c in dtypes},
+                )
+                .with_columns(
+                    [
+                        (pl.col(name) / 1000).alias(name.replace("_us", "_ms"))
+                        for name in us_columns
+                    ]
+                )
+                .drop(us_columns)
+            )
         df = (
             df.with_columns(
                 (pl.col("unix_timestamp_nano"))
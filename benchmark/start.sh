@@ -84,11 +84,6 @@ start_benchmark_client_service() {
   "
 }
 
-download_benchmark_client_output_files() {
-  local local_dir=$1
-  gcloud compute scp --zone="$ZONE" --recurse "${BENCHMARK_CLIENT_INSTANCE}":"$BENCHMARK_DATA_DIR/*" "$local_dir"
-}
-
 cleanup_benchmark_client_output_files() {
   gcloud compute ssh "${BENCHMARK_CLIENT_INSTANCE}" --zone="$ZONE" --command="
   rm -rf $BENCHMARK_DATA_DIR/*
@@ -232,12 +227,9 @@ run_benchmark() {
     echo "Starting benchmark client service..."
     start_benchmark_client_service
 
-    # run the benchmark
-    $BENCHMARK_CONTROL_BIN run "$benchmark_client_pubic_ip:$BENCHMARK_CLIENT_GRPC_PORT"
-
-    # download the benchmark client output files
-    echo "Downloading benchmark client output files..."
-    download_benchmark_client_output_files "$output_dir"
+    # run the benchmark, the benchmark client output files are downloaded
+    # into the output directory once the run finishes
+    $BENCHMARK_CONTROL_BIN run -o "$output_dir" "$benchmark_client_pubic_ip:$BENCHMARK_CLIENT_GRPC_PORT"
 
     # cleanup the benchmark client output files
     echo "Cleaning up benchmark client output files..."
@@ -3,6 +3,7 @@ module csb/client
 go 1.23
 
 require (
+	github.com/HdrHistogram/hdrhistogram-go v1.1.2
 	go.etcd.io/etcd/api/v3 v3.5.17
 	go.etcd.io/etcd/client/v3 v3.5.17
 	go.uber.org/zap v1.17.0
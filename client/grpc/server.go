@@ -4,24 +4,46 @@ import (
 	pb "csb/api/benchmarkpb"
 	logger "csb/client/logger"
 	config "csb/control/config"
+	"csb/control/constants"
+	"csb/control/transfer"
 	"encoding/json"
 	"errors"
 	"io"
+	"os"
+	"slices"
 	"sync"
+	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// ClientAssignment describes the role of this benchmark client in a run
+// that is driven by the control program against several benchmark clients
+type ClientAssignment struct {
+	Index          int   // position of this client among all benchmark clients
+	NumClients     int   // total number of benchmark clients in the run
+	SeedOffset     int64 // offset added to the seed of the per-goroutine random generators
+	ClientIDOffset int   // offset added to the goroutine IDs reported in the metrics
+}
+
 type BenchmarkServiceServer struct {
 	pb.UnimplementedBenchmarkServiceServer
-	keys       []string
-	keysMu     sync.RWMutex
-	ctlConfig  *config.BenchctlConfig
-	grpcServer *grpc.Server
-	termChan   chan struct{}
-	currStream pb.BenchmarkService_CTRLStreamServer
-	streamMu   sync.Mutex
-	logger     *logger.Logger
+	keys        []string
+	keysMu      sync.RWMutex
+	ctlConfig   *config.BenchctlConfig
+	assignment  ClientAssignment
+	grpcServer  *grpc.Server
+	termChan    chan struct{}
+	prepareCh   chan struct{}
+	prepareOnce sync.Once
+	startAtCh   chan time.Time
+	currStream  pb.BenchmarkService_CTRLStreamServer
+	streamMu    sync.Mutex
+	logger      *logger.Logger
+	// receives the trace file of the replay scenario
+	trace *transfer.Receiver
 }
 
 func NewBenchmarkServiceServer(grpcserver *grpc.Server, logger *logger.Logger, termChan chan struct{}) *BenchmarkServiceServer {
@@ -29,6 +51,9 @@ func NewBenchmarkServiceServer(grpcserver *grpc.Server, logger *logger.Logger, t
 		keys:       make([]string, 0),
 		grpcServer: grpcserver,
 		termChan:   termChan,
+		prepareCh:  make(chan struct{}),
+		trace:      transfer.NewReceiver("."),
+		startAtCh:  make(chan time.Time, 1),
 		logger:     logger,
 	}
 }
@@ -41,6 +66,20 @@ func (s *BenchmarkServiceServer) GetConfig() *config.BenchctlConfig {
 	return s.ctlConfig
 }
 
+// PrepareRequested is closed once the control program asks to load the data
+func (s *BenchmarkServiceServer) PrepareRequested() <-chan struct{} {
+	return s.prepareCh
+}
+
+// StartTime delivers the wall-clock instant at which the benchmark starts
+func (s *BenchmarkServiceServer) StartTime() <-chan time.Time {
+	return s.startAtCh
+}
+
+func (s *BenchmarkServiceServer) GetAssignment() ClientAssignment {
+	return s.assignment
+}
+
 func (s *BenchmarkServiceServer) GetKeys() []string {
 	s.keysMu.RLock()
 	defer s.keysMu.RUnlock()
@@ -76,6 +115,44 @@ func (s *BenchmarkServiceServer) SendBenchmarkStatus(status string) {
 	}
 }
 
+func (s *BenchmarkServiceServer) SendStepReport(report *pb.StepReport) {
+	msg := &pb.CTRLMessage{
+		Payload: &pb.CTRLMessage_StepReport{
+			StepReport: report,
+		},
+	}
+	err := s.SendCTRLMessage(msg)
+	if err != nil {
+		s.logger.Printf("Error sending step report message: %v", err)
+	}
+}
+
+func (s *BenchmarkServiceServer) SendCapacityReport(report *pb.CapacityReport) {
+	msg := &pb.CTRLMessage{
+		Payload: &pb.CTRLMessage_CapacityReport{
+			CapacityReport: report,
+		},
+	}
+	err := s.SendCTRLMessage(msg)
+	if err != nil {
+		s.logger.Printf("Error sending capacity report message: %v", err)
+	}
+}
+
+func (s *BenchmarkServiceServer) SendPrepareDone(numKeys int) {
+	msg := &pb.CTRLMessage{
+		Payload: &pb.CTRLMessage_PrepareDone{
+			PrepareDone: &pb.PrepareDone{
+				NumKeys: int64(numKeys),
+			},
+		},
+	}
+	err := s.SendCTRLMessage(msg)
+	if err != nil {
+		s.logger.Printf("Error sending prepare done message: %v", err)
+	}
+}
+
 func (s *BenchmarkServiceServer) CTRLStream(stream pb.BenchmarkService_CTRLStreamServer) error {
 	// Store stream for server-initiated messages
 	s.streamMu.Lock()
@@ -110,13 +187,23 @@ func (s *BenchmarkServiceServer) CTRLStream(stream pb.BenchmarkService_CTRLStrea
 			switch payload := req.Payload.(type) {
 			case *pb.CTRLMessage_ConfigFile:
 				bytes := payload.ConfigFile.GetContent()
-				err = json.Unmarshal(bytes, &s.ctlConfig)
+				var ctlConfig *config.BenchctlConfig
+				err = json.Unmarshal(bytes, &ctlConfig)
 				if err != nil {
 					s.logger.Printf("Error unmarshalling config file: %v", err)
 					return err
 				}
+				s.assignment = ClientAssignment{
+					Index:          int(payload.ConfigFile.GetClientIndex()),
+					NumClients:     max(int(payload.ConfigFile.GetNumBenchClients()), 1),
+					SeedOffset:     payload.ConfigFile.GetSeedOffset(),
+					ClientIDOffset: int(payload.ConfigFile.GetClientIdOffset()),
+				}
+				// the config is set last as it marks the server as ready
+				s.ctlConfig = ctlConfig
 				configPretty, _ := json.MarshalIndent(s.ctlConfig, "", "  ")
 				s.logger.Printf("Received config file:\n %s", string(configPretty))
+				s.logger.Printf("Running as benchmark client %d of %d", s.assignment.Index+1, s.assignment.NumClients)
 				response := &pb.CTRLMessage{
 					Payload: &pb.CTRLMessage_ConfigFileResponse{
 						ConfigFileResponse: &pb.ConfigFileResponse{
@@ -125,6 +212,22 @@ func (s *BenchmarkServiceServer) CTRLStream(stream pb.BenchmarkService_CTRLStrea
 					},
 				}
 				err = stream.Send(response)
+			case *pb.CTRLMessage_TraceChunk:
+				if err = s.receiveTraceChunk(payload.TraceChunk); err != nil {
+					s.logger.Printf("Error receiving trace file: %v", err)
+					return err
+				}
+			case *pb.CTRLMessage_Prepare:
+				s.logger.Printf("Received prepare message from client")
+				s.prepareOnce.Do(func() { close(s.prepareCh) })
+			case *pb.CTRLMessage_StartAt:
+				startTime := time.Unix(0, payload.StartAt.GetUnixNano())
+				s.logger.Printf("Received start time from client: %v", startTime)
+				select {
+				case s.startAtCh <- startTime:
+				default:
+					s.logger.Printf("Ignoring start time, the benchmark start is already scheduled")
+				}
 			case *pb.CTRLMessage_Shutdown:
 				s.logger.Printf("Received shutdown message from client")
 				close(s.termChan)
@@ -138,3 +241,66 @@ func (s *BenchmarkServiceServer) CTRLStream(stream pb.BenchmarkService_CTRLStrea
 
 	}
 }
+
+// receiveTraceChunk writes a chunk of the trace file, the file is only put
+// in place once its checksum is verified
+func (s *BenchmarkServiceServer) receiveTraceChunk(chunk *pb.FileChunk) error {
+	path, err := s.trace.Receive(chunk)
+	if err != nil || path == "" {
+		return err
+	}
+	s.logger.Printf("Received trace file %s (%d bytes)", path, chunk.GetOffset()+int64(len(chunk.GetData())))
+	return nil
+}
+
+// resultFiles returns the names of the files produced by a benchmark run, a
+// restarted benchmark client which has not received a configuration yet
+// offers the metrics file of the default configuration
+func (s *BenchmarkServiceServer) resultFiles() []string {
+	metricsFile := constants.DEFAULT_METRICS_FILE
+	if s.ctlConfig != nil {
+		metricsFile = s.ctlConfig.MetricsFile
+	}
+	return []string{metricsFile, constants.DEFAULT_BENCH_RUN_LOG_FILE, constants.DEFAULT_KEY_FILE, constants.DEFAULT_DB_STATS_FILE}
+}
+
+// PullResults streams the result files of the benchmark run to the control
+// program in chunks, files which do not exist yet are skipped unless they
+// are requested explicitly
+func (s *BenchmarkServiceServer) PullResults(req *pb.PullResultsRequest, stream pb.BenchmarkService_PullResultsServer) error {
+	available := s.resultFiles()
+	files := req.GetFiles()
+	explicit := len(files) > 0
+	if !explicit {
+		files = available
+	}
+
+	for _, name := range files {
+		if !slices.Contains(available, name) {
+			return status.Errorf(codes.InvalidArgument, "unknown result file %s", name)
+		}
+		if name == constants.DEFAULT_BENCH_RUN_LOG_FILE {
+			if err := s.logger.Flush(); err != nil {
+				s.logger.Printf("Error flushing log file: %v", err)
+			}
+		}
+		err := s.sendFile(name, stream)
+		if os.IsNotExist(err) && !explicit {
+			s.logger.Printf("Skipping result file %s, it does not exist", name)
+			continue
+		}
+		if os.IsNotExist(err) {
+			return status.Errorf(codes.NotFound, "result file %s does not exist", name)
+		}
+		if err != nil {
+			s.logger.Printf("Error sending result file %s: %v", name, err)
+			return err
+		}
+		s.logger.Printf("Sent result file %s", name)
+	}
+	return nil
+}
+
+func (s *BenchmarkServiceServer) sendFile(name string, stream pb.BenchmarkService_PullResultsServer) error {
+	return transfer.SendFile(name, name, stream.Send)
+}
@@ -5,13 +5,33 @@ import (
 	"io"
 	"log"
 	"os"
+	"sync"
 )
 
 // Logger wraps the standard logger and file handle
 type Logger struct {
 	*log.Logger
 	file   *os.File
-	writer *bufio.Writer
+	writer *lockedWriter
+}
+
+// lockedWriter serializes the access to the buffered log file, so that the
+// log file can be flushed while other goroutines are logging
+type lockedWriter struct {
+	mu sync.Mutex
+	w  *bufio.Writer
+}
+
+func (lw *lockedWriter) Write(p []byte) (int, error) {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	return lw.w.Write(p)
+}
+
+func (lw *lockedWriter) Flush() error {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	return lw.w.Flush()
 }
 
 // Close properly flushes and closes the log file
@@ -33,12 +53,12 @@ func (l *Logger) Flush() error {
 }
 
 func NewLogger(filename string) (*Logger, error) {
-	logFile, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
+	logFile, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
 	if err != nil {
 		return nil, err
 	}
 
-	bufferedWriter := bufio.NewWriter(logFile)
+	bufferedWriter := &lockedWriter{w: bufio.NewWriter(logFile)}
 
 	// Write log to both stdout and log file
 	multiWriter := io.MultiWriter(os.Stdout, bufferedWriter)
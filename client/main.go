@@ -8,6 +8,7 @@ import (
 	"net"
 	"os"
 	"os/signal"
+	"sort"
 	"strings"
 	"sync"
 	"syscall"
@@ -17,6 +18,7 @@ import (
 	grpcserver "csb/client/grpc"
 	lg "csb/client/logger"
 	runner "csb/client/runner"
+	benchCfg "csb/control/config"
 	constants "csb/control/constants"
 	dg "csb/data-generator"
 	"log"
@@ -33,7 +35,7 @@ var logger *lg.Logger
 
 func init() {
 	var err error
-	logger, err = lg.NewLogger("run.log")
+	logger, err = lg.NewLogger(constants.DEFAULT_BENCH_RUN_LOG_FILE)
 	if err != nil {
 		log.Fatalf("Failed to create logger: %v", err)
 	}
@@ -94,18 +96,20 @@ func main() {
 	go func() {
 		<-readyChan
 		benchCfg := benchmarkServiceServer.GetConfig()
+		logger.Println("Waiting for the control program to request the data preparation ...")
+		<-benchmarkServiceServer.PrepareRequested()
 		logger.Printf("Generating and loading data into the database ...")
 		benchmarkServiceServer.SendBenchmarkStatus("Start generating and loading data into the database")
 		load_db(benchmarkServiceServer)
-		if benchCfg.Scenario == constants.SCENARIO_KV_STORE {
-			logger.Println("Running KV store benchmark ...")
-			benchmarkServiceServer.SendBenchmarkStatus("Start running KV store benchmark ...")
-			runBenchmarkKV(benchmarkServiceServer)
-		} else {
-			logger.Println("Running Lock service benchmark ...")
-			benchmarkServiceServer.SendBenchmarkStatus("Start running Lock service benchmark")
-			runBenchmarkLockService(benchmarkServiceServer)
-		}
+		benchmarkServiceServer.SendPrepareDone(len(benchmarkServiceServer.GetKeys()))
+
+		// all benchmark clients of a run start at the same wall-clock instant
+		logger.Println("Waiting for the start time of the benchmark ...")
+		startTime := <-benchmarkServiceServer.StartTime()
+		logger.Printf("Benchmark starts at %v", startTime)
+		logger.Printf("Running %s benchmark with %s workload ...", benchCfg.Scenario, benchCfg.WorkloadType)
+		benchmarkServiceServer.SendBenchmarkStatus(fmt.Sprintf("Start running %s benchmark with %s workload ...", benchCfg.Scenario, benchCfg.WorkloadType))
+		runBenchmark(benchmarkServiceServer, startTime)
 		err = benchmarkServiceServer.SendCTRLMessage(&pb.CTRLMessage{
 			Payload: &pb.CTRLMessage_BenchmarkFinished{},
 		})
@@ -137,13 +141,41 @@ func load_db(s *grpcserver.BenchmarkServiceServer) {
 	rg := rand.New(rand.NewSource(ctlConfig.Seed))
 	dataGenerator := dg.NewGenerator(rg)
 	s.SendBenchmarkStatus("Generating synthetic data ...")
-	data, err := dataGenerator.GenerateData(ctlConfig.NumKeys, ctlConfig.KeySize, ctlConfig.ValueSize)
+	values, err := runner.NewValueGenerator(ctlConfig)
+	if err != nil {
+		logger.Printf("Failed to create value generator: %v\n", err)
+		exit(1)
+	}
+	var data map[string][]byte
+	numKeys := ctlConfig.NumKeys
+	if ctlConfig.Scenario == constants.SCENARIO_KUBERNETES {
+		// the objects of the cluster take the place of the synthetic keys
+		var objects []dg.ObjectCount
+		objects, err = runner.GetObjectCounts(ctlConfig)
+		if err == nil {
+			numKeys = 0
+			for _, o := range objects {
+				numKeys += o.Count
+			}
+			data, err = dataGenerator.GenerateObjects(objects, ctlConfig.K8sNamespaces, values)
+		}
+	} else if ctlConfig.Scenario == constants.SCENARIO_REPLAY {
+		// the keys of the trace take the place of the synthetic keys
+		var records []benchCfg.TraceRecord
+		records, err = benchCfg.ReadTraceCSV(constants.DEFAULT_TRACE_FILE)
+		if err == nil {
+			data, err = runner.TraceData(records, values, rg)
+			numKeys = len(data)
+		}
+	} else {
+		data, err = dataGenerator.GenerateData(ctlConfig.NumKeys, ctlConfig.KeySize, values)
+	}
 
 	logger.Println("Number of key-value paris generated: ", len(data))
 	s.SendBenchmarkStatus(fmt.Sprintf("Number of key-value pairs generated: %d", len(data)))
 
-	if ctlConfig.NumKeys != len(data) {
-		logger.Printf("Failed to generate the required number of key-value pairs due to collision: %d\n", ctlConfig.NumKeys)
+	if numKeys != len(data) {
+		logger.Printf("Failed to generate the required number of key-value pairs due to collision: %d\n", numKeys)
 	}
 
 	if err != nil {
@@ -203,13 +235,23 @@ func load_db(s *grpcserver.BenchmarkServiceServer) {
 		}()
 	}
 
-	// Send tasks to workers
-	for key, value := range data {
+	// Every benchmark client generates the same data set, the keys are sorted
+	// so that each client loads a disjoint share of it into the database
+	for key := range data {
 		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// Send tasks to workers
+	assignment := s.GetAssignment()
+	for i, key := range keys {
+		if i%assignment.NumClients != assignment.Index {
+			continue
+		}
 		tasks <- struct {
 			key   string
 			value []byte
-		}{key, value}
+		}{key, data[key]}
 	}
 	close(tasks) // Close the task channel to signal workers to stop
 
@@ -225,57 +267,21 @@ func load_db(s *grpcserver.BenchmarkServiceServer) {
 	s.SendBenchmarkStatus("Synthetic data generated and loaded successfully")
 }
 
-func runBenchmarkKV(s *grpcserver.BenchmarkServiceServer) {
-	config := s.GetConfig()
-
-	readPercent, writePercent, err := runner.GetRWPercentages(config.WorkloadType)
-
-	if err != nil {
-		logger.Printf("Invalid workload type %s, %v", config.WorkloadType, err)
-		exit(1)
-	}
-
-	runConfig := &runner.BenchmarkRunConfig{
-		BenchctlConfig:   *config,
-		ReadPercent:      readPercent,
-		WritePercent:     writePercent,
-		Keys:             s.GetKeys(),
-		MetricsBatchSize: constants.DEFAULT_METRICS_BATCH_SIZE,
-	}
-
-	bench, err := runner.NewBenchmarkRunnerKV(runConfig, logger)
-	if err != nil {
-		s.SendBenchmarkStatus("Failed to create benchmark runner")
-		logger.Printf("Failed to create benchmark runner: %v", err)
-		exit(1)
-	}
-	defer bench.Close()
-
-	if err := bench.Run(s); err != nil {
-		s.SendBenchmarkStatus("Benchmark failed")
-		logger.Printf("Benchmark failed: %v", err)
-		exit(1)
-	}
-
-	log.Printf("Benchmark completed. Overall results:")
-	s.SendBenchmarkStatus("Benchmark completed. Overall results:")
-	for _, result := range bench.GetResults() {
-		resultStr := fmt.Sprintf("Step with #Clients: %d, P99 Latency: %v, #Operations: %d, #Errors: %d", result.NumClients, result.P99Latency, result.Operations, result.Errors)
-		logger.Println(resultStr)
-		s.SendBenchmarkStatus(resultStr)
-	}
-}
-
-func runBenchmarkLockService(s *grpcserver.BenchmarkServiceServer) {
+func runBenchmark(s *grpcserver.BenchmarkServiceServer, startTime time.Time) {
 	config := s.GetConfig()
 
 	runConfig := &runner.BenchmarkRunConfig{
 		BenchctlConfig:   *config,
 		Keys:             s.GetKeys(),
 		MetricsBatchSize: constants.DEFAULT_METRICS_BATCH_SIZE,
+		SeedOffset:       s.GetAssignment().SeedOffset,
+		ClientIDOffset:   s.GetAssignment().ClientIDOffset,
+		ClientIndex:      s.GetAssignment().Index,
+		NumBenchClients:  s.GetAssignment().NumClients,
+		StartTime:        startTime,
 	}
 
-	bench, err := runner.NewBenchmarkRunnerLock(runConfig, logger)
+	bench, err := runner.NewBenchmarkRunner(runConfig, logger)
 	if err != nil {
 		s.SendBenchmarkStatus("Failed to create benchmark runner")
 		logger.Printf("Failed to create benchmark runner: %v", err)
@@ -289,12 +295,12 @@ func runBenchmarkLockService(s *grpcserver.BenchmarkServiceServer) {
 		exit(1)
 	}
 
-	log.Printf("Benchmark completed. Overall results:")
-	s.SendBenchmarkStatus("Benchmark completed. Overall results:")
+	// the control program receives the step results as structured reports
+	logger.Printf("Benchmark completed. Overall results:")
+	s.SendBenchmarkStatus("Benchmark completed")
 	for _, result := range bench.GetResults() {
 		resultStr := fmt.Sprintf("Step with #Clients: %d, P99 Latency: %v, #Operations: %d, #Errors: %d", result.NumClients, result.P99Latency, result.Operations, result.Errors)
 		logger.Println(resultStr)
-		s.SendBenchmarkStatus(resultStr)
 	}
 }
 
@@ -0,0 +1,107 @@
+package runner
+
+import (
+	pb "csb/api/benchmarkpb"
+	grpcserver "csb/client/grpc"
+	lg "csb/client/logger"
+	benchCfg "csb/control/config"
+	"time"
+)
+
+// capacitySearch looks for the highest load level at which the latency
+// percentile of the successful operations stays within the SLA and the
+// error rate does not exceed its maximum. The level is doubled until the SLA
+// is violated, afterwards the range between the highest passing and the
+// lowest failing level is bisected until it is not wider than the step size.
+type capacitySearch struct {
+	slaLatency    time.Duration
+	slaPercentile float64
+	maxErrorRate  float64
+	initial       int
+	resolution    int
+	maxLevel      int
+	stepDuration  time.Duration
+
+	passed int         // highest level within the SLA, 0 if none
+	failed int         // lowest level violating the SLA, 0 if none
+	knee   *StepResult // passing step with the highest throughput
+}
+
+func (c *capacitySearch) next() (benchCfg.LoadStep, bool) {
+	level, ok := c.nextLevel()
+	return benchCfg.LoadStep{Level: level, Duration: c.stepDuration}, ok
+}
+
+func (c *capacitySearch) nextLevel() (int, bool) {
+	if c.failed == 0 {
+		switch {
+		case c.passed == 0:
+			return c.initial, true
+		case c.passed >= c.maxLevel:
+			return 0, false
+		default:
+			return min(2*c.passed, c.maxLevel), true
+		}
+	}
+	if c.failed-c.passed <= c.resolution {
+		return 0, false
+	}
+	return c.passed + (c.failed-c.passed)/2, true
+}
+
+func (c *capacitySearch) observe(step benchCfg.LoadStep, result *StepResult) {
+	level := step.Level
+	if c.withinSLA(result) {
+		c.passed = max(c.passed, level)
+		if c.knee == nil || result.Throughput() > c.knee.Throughput() {
+			c.knee = result
+		}
+		return
+	}
+	if c.failed == 0 || level < c.failed {
+		c.failed = level
+	}
+}
+
+func (c *capacitySearch) withinSLA(result *StepResult) bool {
+	return result.Operations > result.Errors &&
+		result.ErrorRate() <= c.maxErrorRate &&
+		result.SuccessPercentile(c.slaPercentile) <= c.slaLatency
+}
+
+// converged reports whether the search narrowed down the capacity before
+// the total duration ran out
+func (c *capacitySearch) converged() bool {
+	_, more := c.nextLevel()
+	return !more
+}
+
+// report summarizes the search for the control program
+func (c *capacitySearch) report() *pb.CapacityReport {
+	report := &pb.CapacityReport{
+		Found:         c.knee != nil,
+		Converged:     c.converged(),
+		SlaPercentile: c.slaPercentile,
+		SlaLatencyUs:  c.slaLatency.Microseconds(),
+	}
+	if c.knee != nil {
+		report.Knee = c.knee.ToStepReport()
+		report.LatencyUs = c.knee.SuccessPercentile(c.slaPercentile).Microseconds()
+	}
+	return report
+}
+
+// reportCapacity logs the outcome of the capacity search and sends it to the
+// control program
+func reportCapacity(s *grpcserver.BenchmarkServiceServer, logger *lg.Logger, search *capacitySearch) {
+	report := search.report()
+	switch {
+	case !report.Found:
+		logger.Printf("Capacity search found no step within the SLA (P%g <= %v, error rate <= %g)", search.slaPercentile*100, search.slaLatency, search.maxErrorRate)
+	case !report.Converged:
+		logger.Printf("Capacity search did not converge within the total duration, best step so far: %s, %.1f ops/s", describeLoad(search.knee.NumClients, search.knee.TargetRate), report.Knee.Throughput)
+	default:
+		logger.Printf("Capacity found at %s, %.1f ops/s (P%g: %v)", describeLoad(search.knee.NumClients, search.knee.TargetRate), report.Knee.Throughput, search.slaPercentile*100, time.Duration(report.LatencyUs)*time.Microsecond)
+	}
+	s.SendCapacityReport(report)
+}
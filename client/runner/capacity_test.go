@@ -0,0 +1,104 @@
+package runner
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// stepWith returns the result of a step with the given successful and failed
+// operations
+func stepWith(successes int, successLatency time.Duration, failures int, failureLatency time.Duration) *StepResult {
+	result := newStepResult(1, 1, 0, false)
+	for i := 0; i < successes; i++ {
+		result.addOperation(operationSample{label: "read", latency: successLatency})
+	}
+	for i := 0; i < failures; i++ {
+		result.addOperation(operationSample{label: "read", latency: failureLatency, err: errors.New("failed")})
+	}
+	result.EndTime = result.StartTime.Add(time.Second)
+	result.calculateLatencies()
+	return result
+}
+
+func TestCapacitySearchWithinSLA(t *testing.T) {
+	search := &capacitySearch{slaLatency: 10 * time.Millisecond, slaPercentile: 0.99, maxErrorRate: 0.01}
+	tests := []struct {
+		name   string
+		result *StepResult
+		want   bool
+	}{
+		{"fast successes", stepWith(1000, time.Millisecond, 0, 0), true},
+		{"slow successes", stepWith(1000, 20*time.Millisecond, 0, 0), false},
+		{"mostly failed", stepWith(10, time.Millisecond, 990, time.Millisecond), false},
+		{"few slow failures", stepWith(995, time.Millisecond, 5, time.Second), true},
+		{"no operations", stepWith(0, 0, 0, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := search.withinSLA(tt.result); got != tt.want {
+				t.Errorf("withinSLA() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCapacitySearchBisection(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxLevel  int
+		capacity  int // highest level within the SLA
+		wantSteps []int
+		wantKnee  int
+	}{
+		{"doubles then bisects", 100, 37, []int{4, 8, 16, 32, 64, 48, 40, 36, 38}, 36},
+		{"capped at the maximum", 20, 100, []int{4, 8, 16, 20}, 20},
+		{"bisects below the initial level", 100, 2, []int{4, 2}, 2},
+		{"no level within the SLA", 100, 0, []int{4, 2}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			search := &capacitySearch{
+				slaLatency:    10 * time.Millisecond,
+				slaPercentile: 0.99,
+				maxErrorRate:  0.01,
+				initial:       4,
+				resolution:    2,
+				maxLevel:      tt.maxLevel,
+				stepDuration:  time.Second,
+			}
+			var levels []int
+			for len(levels) < 20 {
+				step, ok := search.next()
+				if !ok {
+					break
+				}
+				levels = append(levels, step.Level)
+				latency := time.Millisecond
+				if step.Level > tt.capacity {
+					latency = 20 * time.Millisecond
+				}
+				result := stepWith(step.Level*100, latency, 0, 0)
+				result.NumClients = step.Level
+				search.observe(step, result)
+			}
+
+			if !slices.Equal(levels, tt.wantSteps) {
+				t.Errorf("levels = %v, want %v", levels, tt.wantSteps)
+			}
+			if !search.converged() {
+				t.Error("converged() = false after the last step")
+			}
+			report := search.report()
+			if report.Found != (tt.wantKnee > 0) {
+				t.Fatalf("report found = %v, want %v", report.Found, tt.wantKnee > 0)
+			}
+			if tt.wantKnee > 0 && search.knee.NumClients != tt.wantKnee {
+				t.Errorf("knee at %d clients, want %d", search.knee.NumClients, tt.wantKnee)
+			}
+		})
+	}
+}
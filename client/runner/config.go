@@ -6,80 +6,231 @@ import (
 	"csb/control/constants"
 	generator "csb/data-generator"
 	"errors"
+	"fmt"
 	"math/rand"
 	"sync"
 	"time"
 
+	"github.com/HdrHistogram/hdrhistogram-go"
 	clientv3 "go.etcd.io/etcd/client/v3"
-	"go.etcd.io/etcd/client/v3/concurrency"
 )
 
 // BenchmarkRunConfig holds all configuration parameters
 type BenchmarkRunConfig struct {
 	benchCfg.BenchctlConfig
 
-	// Workload parameters
-	ReadPercent  int
-	WritePercent int
-
 	// Keys to operate on
 	Keys []string
 
+	// Offsets assigned by the control program when several benchmark clients
+	// run against the same cluster, they keep random streams and client IDs
+	// distinct across benchmark clients
+	SeedOffset     int64
+	ClientIDOffset int
+	// Position of this benchmark client among the benchmark clients of the
+	// run, e.g. to split a trace between them
+	ClientIndex     int
+	NumBenchClients int
+
+	// Wall-clock instant at which the warm-up starts, the load steps follow
+	// back to back from there so that all benchmark clients change their
+	// load at the same time
+	StartTime time.Time
+
 	// Metrics parameters
 	MetricsBatchSize int
 }
 
-func GetRWPercentages(w string) (int, int, error) {
-	//workload read, write distribution
-	var readPercent, writePercent int
-	switch w {
-	case constants.WORKLOAD_TYPE_READ_HEAVY:
-		readPercent, writePercent = 95, 5
-	case constants.WORKLOAD_TYPE_UPDATE_HEAVY:
-		readPercent, writePercent = 50, 50
-	case constants.WORKLOAD_TYPE_READ_ONLY:
-		readPercent, writePercent = 100, 0
-	default:
-		return 0, 0, errors.New("unknown workload type")
+// Operation mixes of the kv-store workload types, in the format of the
+// operation mix of the config
+var workloadMixes = map[string][]string{
+	constants.WORKLOAD_TYPE_READ_HEAVY:   {"get:95", "put:5"},
+	constants.WORKLOAD_TYPE_UPDATE_HEAVY: {"get:50", "put:50"},
+	constants.WORKLOAD_TYPE_READ_ONLY:    {"get:100"},
+	constants.WORKLOAD_TYPE_TXN_CAS:      {"txn:100:keys=1"},
+	constants.WORKLOAD_TYPE_TXN_MULTI:    {"txn:100"},
+	constants.WORKLOAD_TYPE_READ_COMPARE: {"get:100"},
+	constants.WORKLOAD_TYPE_MVCC_HISTORY: {"put-prevkv:50", "get-rev:30", "history:20"},
+	constants.WORKLOAD_TYPE_LARGE_OBJECT: {"get:50", "put:50"},
+	constants.WORKLOAD_TYPE_YCSB_A:       {"get:50", "put:50"},
+	constants.WORKLOAD_TYPE_YCSB_B:       {"get:95", "put:5"},
+	constants.WORKLOAD_TYPE_YCSB_C:       {"get:100"},
+	constants.WORKLOAD_TYPE_YCSB_D:       {"get:95", "insert:5"},
+	constants.WORKLOAD_TYPE_YCSB_E:       {"scan:95", "insert:5"},
+	constants.WORKLOAD_TYPE_YCSB_F:       {"get:50", "rmw:50"},
+}
+
+// Request distributions of the YCSB core workloads, they replace the
+// configured key distribution
+var workloadKeyDistributions = map[string]string{
+	constants.WORKLOAD_TYPE_YCSB_A: constants.KEY_DISTRIBUTION_SCRAMBLED_ZIPFIAN,
+	constants.WORKLOAD_TYPE_YCSB_B: constants.KEY_DISTRIBUTION_SCRAMBLED_ZIPFIAN,
+	constants.WORKLOAD_TYPE_YCSB_C: constants.KEY_DISTRIBUTION_SCRAMBLED_ZIPFIAN,
+	constants.WORKLOAD_TYPE_YCSB_D: constants.KEY_DISTRIBUTION_LATEST,
+	constants.WORKLOAD_TYPE_YCSB_E: constants.KEY_DISTRIBUTION_SCRAMBLED_ZIPFIAN,
+	constants.WORKLOAD_TYPE_YCSB_F: constants.KEY_DISTRIBUTION_SCRAMBLED_ZIPFIAN,
+}
+
+// ycsbZipfianTheta is the zipfian constant of the YCSB core workloads
+const ycsbZipfianTheta = 0.99
+
+// GetKeyDistribution returns the key distribution of the workload, the YCSB
+// workload types use the request distribution of the core workload
+func GetKeyDistribution(config *BenchmarkRunConfig) string {
+	if dist, ok := workloadKeyDistributions[config.WorkloadType]; ok {
+		return dist
+	}
+	return config.KeyDistribution
+}
+
+// GetZipfianTheta returns the exponent of the zipfian distributions, the
+// YCSB workload types use the zipfian constant of the core workloads
+func GetZipfianTheta(config *BenchmarkRunConfig) float64 {
+	if _, ok := workloadKeyDistributions[config.WorkloadType]; ok {
+		return ycsbZipfianTheta
+	}
+	return config.ZipfianTheta
+}
+
+// GetOperationMix returns the operation mix of the kv-store workload, the
+// configured mix replaces the preset of the workload type. The range percent
+// turns the given share of the operations of a preset into range reads.
+func GetOperationMix(config *BenchmarkRunConfig) ([]benchCfg.Operation, error) {
+	if len(config.OperationMix) > 0 {
+		return benchCfg.ParseOperationMix(config.OperationMix)
+	}
+	preset, ok := workloadMixes[config.WorkloadType]
+	if !ok {
+		return nil, errors.New("unknown workload type")
+	}
+	ops, err := benchCfg.ParseOperationMix(preset)
+	if err != nil {
+		return nil, err
+	}
+	if config.RangePercent > 0 {
+		totalWeight := 0
+		for i := range ops {
+			totalWeight += ops[i].Weight
+			ops[i].Weight *= 100 - config.RangePercent
+		}
+		ops = append(ops, benchCfg.Operation{
+			Name:   constants.OPERATION_RANGE,
+			Weight: config.RangePercent * totalWeight,
+			Params: map[string]string{},
+		})
+	}
+	return ops, nil
+}
+
+// NewValueGenerator returns the generator of the values written by the
+// benchmark, the sizes and the content of the values follow the config. The
+// large-object workload type replaces the value size distribution with
+// sizes from the minimum large value size up to the max request size.
+func NewValueGenerator(config *benchCfg.BenchctlConfig) (*generator.ValueGenerator, error) {
+	opts := generator.ValueOptions{
+		Distribution: config.ValueSizeDistribution,
+		Size:         config.ValueSize,
+		MinSize:      config.ValueSizeMin,
+		MaxSize:      config.ValueSizeMax,
+		StdDev:       config.ValueSizeStdDev,
+		Sigma:        config.ValueSizeSigma,
+		Content:      config.ValueContent,
+	}
+	if config.WorkloadType == constants.WORKLOAD_TYPE_LARGE_OBJECT {
+		opts.Distribution = constants.VALUE_SIZE_UNIFORM
+		opts.MinSize = config.LargeValueMin
+		opts.MaxSize = config.MaxRequestBytes
+		return generator.NewValueGenerator(opts)
+	}
+	if config.ValueSizeDistribution == constants.VALUE_SIZE_EMPIRICAL {
+		histogram, err := benchCfg.ParseValueSizeHistogram(config.ValueSizeHistogram)
+		if err != nil {
+			return nil, err
+		}
+		for _, bucket := range histogram {
+			opts.Sizes = append(opts.Sizes, bucket.Size)
+			opts.Weights = append(opts.Weights, bucket.Weight)
+		}
+	}
+	return generator.NewValueGenerator(opts)
+}
+
+// GetObjectCounts returns the number of objects of every resource type of
+// the kubernetes scenario
+func GetObjectCounts(config *benchCfg.BenchctlConfig) ([]generator.ObjectCount, error) {
+	counts, err := benchCfg.ParseObjectCounts(config.K8sObjects)
+	if err != nil {
+		return nil, err
+	}
+	objects := make([]generator.ObjectCount, 0, len(counts))
+	for _, c := range counts {
+		objects = append(objects, generator.ObjectCount{Resource: c.Resource, Count: c.Count})
 	}
-	return readPercent, writePercent, nil
+	return objects, nil
+}
+
+// Width of the size classes of large objects, the results of the
+// large-object workload type are reported by size class
+const sizeClassBytes = 256 * 1024
+
+// sizeClass returns the upper bound of the size class of a value in MiB,
+// e.g. "0.50MiB" for values above 256KiB up to 512KiB
+func sizeClass(size int) string {
+	classes := max((size+sizeClassBytes-1)/sizeClassBytes, 1)
+	return fmt.Sprintf("%.2fMiB", float64(classes*sizeClassBytes)/(1<<20))
 }
 
 type StepResult struct {
-	NumClients int
-	StartTime  time.Time
-	EndTime    time.Time
-	Latencies  []time.Duration
-	Operations int64
-	Errors     int64
-	P99Latency time.Duration
+	Index       int    // 0 for the warm-up, main steps are numbered from 1
+	Phase       string // run phase of the step
+	NumClients  int
+	TargetRate  int // target operations per second in open-loop mode, 0 otherwise
+	StartTime   time.Time
+	EndTime     time.Time
+	Latencies   *hdrhistogram.Histogram // latencies in microseconds
+	Operations  int64
+	Errors      int64
+	Bytes       int64         // bytes written and read by the successful operations
+	ErrorCodes  map[int]int64 // number of errors by status code
+	P50Latency  time.Duration
+	P90Latency  time.Duration
+	P99Latency  time.Duration
+	P999Latency time.Duration
+	MaxLatency  time.Duration
+	MeanLatency time.Duration
+	StdDev      time.Duration // standard deviation of the latencies
+	// Results by operation label, e.g. "read/serializable"
+	ByOperation map[string]*OperationResult
+	// Events per second received by every worker, 0 for workloads without events
+	EventsPerWorker float64
+	// Latencies of the successful operations in microseconds, the SLA of the
+	// capacity search applies to them
+	SuccessLatencies *hdrhistogram.Histogram
+	mu               sync.Mutex
 }
 
-// BenchmarkRunner manages the benchmark execution
-type BenchmarkRunnerKV struct {
-	config          *BenchmarkRunConfig
-	clients         []*clientv3.Client
-	results         []*StepResult
-	metricsExporter *MetricsExporter
-	mut             sync.Mutex
-	rand            *rand.Rand
-	generator       *generator.Generator
-	logger          *logger.Logger
+// OperationResult holds the results of the operations of a step with the
+// same label
+type OperationResult struct {
+	Operations int64
+	Errors     int64
+	Bytes      int64
+	Latencies  *hdrhistogram.Histogram // latencies in microseconds
 }
 
-// BenchmarkRunnerLock manages the lock service benchmark
-type BenchmarkRunnerLock struct {
+// BenchmarkRunner manages the benchmark execution, it runs the load steps of
+// the configured workload
+type BenchmarkRunner struct {
 	config          *BenchmarkRunConfig
+	workload        Workload
 	clients         []*clientv3.Client
-	sessions        []*concurrency.Session
 	results         []*StepResult
 	metricsExporter *MetricsExporter
 	mut             sync.Mutex
 	rand            *rand.Rand
 	generator       *generator.Generator
-
-	// Lock-specific configurations
-	lockNames       []string // List of available lock names
-	contentionLevel int      // Number of clients competing for same lock
 	logger          *logger.Logger
+	// Key chooser of every worker, kept across steps
+	keyChoosers   map[int]KeyChooser
+	newKeyChooser func(workerID int) KeyChooser
 }
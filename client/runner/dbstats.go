@@ -0,0 +1,121 @@
+package runner
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// Interval between two samples of the size of the database
+const dbStatsInterval = time.Second
+
+// KeySpaceOwner is implemented by workloads which insert or delete keys, the
+// size of their key space is sampled with the size of the database
+type KeySpaceOwner interface {
+	KeySpace() *KeySpace
+}
+
+// dbStats is a sample of the number of keys and the size of the database
+type dbStats struct {
+	Timestamp    time.Time
+	LiveKeys     int   // keys known to this benchmark client
+	InsertedKeys int64 // keys inserted by this benchmark client
+	DeletedKeys  int64 // keys deleted by this benchmark client
+	Revision     int64 // highest revision of the members
+	DBSize       int64 // largest backend size of the members in bytes
+	DBSizeInUse  int64 // largest logically used backend size of the members in bytes
+}
+
+func (s *dbStats) ToCSVHeader() []string {
+	return []string{
+		"unix_timestamp_nano",
+		"live_keys",
+		"inserted_keys",
+		"deleted_keys",
+		"revision",
+		"db_size_bytes",
+		"db_size_in_use_bytes",
+	}
+}
+
+func (s *dbStats) ToCSVRow() []string {
+	return []string{
+		strconv.FormatInt(s.Timestamp.UnixNano(), 10),
+		strconv.Itoa(s.LiveKeys),
+		strconv.FormatInt(s.InsertedKeys, 10),
+		strconv.FormatInt(s.DeletedKeys, 10),
+		strconv.FormatInt(s.Revision, 10),
+		strconv.FormatInt(s.DBSize, 10),
+		strconv.FormatInt(s.DBSizeInUse, 10),
+	}
+}
+
+// sampleDBStats writes a sample of the key space and the database every
+// interval to the file until the context is done. It has its own etcd
+// client, so that the samples do not wait behind the operations of the
+// workers.
+func (r *BenchmarkRunner) sampleDBStats(ctx context.Context, filename string) error {
+	cli, err := clientv3.New(clientv3.Config{
+		Endpoints:   r.config.Endpoints,
+		DialTimeout: 5 * time.Second,
+		Logger:      zap.NewNop(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create etcd client: %w", err)
+	}
+	defer cli.Close()
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	writer := csv.NewWriter(file)
+	if err := writer.Write((&dbStats{}).ToCSVHeader()); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(dbStatsInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			writer.Flush()
+			return writer.Error()
+		case <-ticker.C:
+		}
+		stats := r.collectDBStats(ctx, cli)
+		if err := writer.Write(stats.ToCSVRow()); err != nil {
+			return err
+		}
+		writer.Flush()
+	}
+}
+
+// collectDBStats samples the key space of the workload and the status of all
+// members, members which do not respond are left out
+func (r *BenchmarkRunner) collectDBStats(ctx context.Context, cli *clientv3.Client) *dbStats {
+	stats := &dbStats{Timestamp: time.Now(), LiveKeys: len(r.config.Keys)}
+	if owner, ok := r.workload.(KeySpaceOwner); ok {
+		stats.LiveKeys, stats.InsertedKeys, stats.DeletedKeys = owner.KeySpace().Stats()
+	}
+	for _, endpoint := range r.config.Endpoints {
+		statusCtx, cancel := context.WithTimeout(ctx, dbStatsInterval)
+		status, err := cli.Status(statusCtx, endpoint)
+		cancel()
+		if err != nil {
+			continue
+		}
+		stats.Revision = max(stats.Revision, status.Header.Revision)
+		stats.DBSize = max(stats.DBSize, status.DbSize)
+		stats.DBSizeInUse = max(stats.DBSizeInUse, status.DbSizeInUse)
+	}
+	return stats
+}
@@ -0,0 +1,208 @@
+package runner
+
+import (
+	benchCfg "csb/control/config"
+	"csb/control/constants"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+)
+
+// KeyChooser picks the keys of the operations of a worker, Next returns the
+// index of the next key among the given number of keys, which grows and
+// shrinks with inserts and deletes. Every worker has its own key chooser,
+// which draws from the random generator of the worker, so that the keys of
+// a worker only depend on the seed and its client ID.
+type KeyChooser interface {
+	Next(rg *rand.Rand, numKeys int) int
+}
+
+// newKeyChooserFactory returns the constructor of the key choosers of the
+// workers, the key choosers of all workers share the constants of the
+// distribution, which are computed for the initial number of keys
+func newKeyChooserFactory(config *BenchmarkRunConfig, numKeys int) (func(workerID int) KeyChooser, error) {
+	if numKeys == 0 {
+		return nil, errors.New("no keys to choose from")
+	}
+	dist := GetKeyDistribution(config)
+	theta := GetZipfianTheta(config)
+	switch dist {
+	case "", constants.KEY_DISTRIBUTION_UNIFORM:
+		chooser := &uniformChooser{}
+		return func(int) KeyChooser { return chooser }, nil
+	case constants.KEY_DISTRIBUTION_ZIPFIAN:
+		chooser := newZipfianChooser(numKeys, theta)
+		return func(int) KeyChooser { return chooser }, nil
+	case constants.KEY_DISTRIBUTION_SCRAMBLED_ZIPFIAN:
+		chooser := &scrambledZipfianChooser{zipfian: newZipfianChooser(numKeys, theta)}
+		return func(int) KeyChooser { return chooser }, nil
+	case constants.KEY_DISTRIBUTION_LATEST:
+		chooser := &latestChooser{zipfian: newZipfianChooser(numKeys, theta)}
+		return func(int) KeyChooser { return chooser }, nil
+	case constants.KEY_DISTRIBUTION_HOTSPOT:
+		chooser := &hotspotChooser{
+			hotKeyRate: float64(config.HotspotKeyPercent) / 100,
+			hotOpRate:  float64(config.HotspotOpPercent) / 100,
+		}
+		return func(int) KeyChooser { return chooser }, nil
+	case constants.KEY_DISTRIBUTION_SEQUENTIAL:
+		// the workers of all benchmark clients start at different keys spread
+		// over the key space
+		workers := benchCfg.MaxWorkers(&config.BenchctlConfig) * max(config.NumBenchClients, 1)
+		stride := max(numKeys/workers, 1)
+		return func(workerID int) KeyChooser {
+			return &sequentialChooser{next: (workerID * stride) % numKeys}
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown key distribution %s", dist)
+	}
+}
+
+// uniformChooser picks every key with the same probability
+type uniformChooser struct{}
+
+func (c *uniformChooser) Next(rg *rand.Rand, numKeys int) int {
+	return rg.Intn(numKeys)
+}
+
+// zipfianChooser picks the keys following a zipfian distribution, the key
+// with index 0 is the most popular one. It is the generator of "Quickly
+// Generating Billion-Record Synthetic Databases" by Gray et al. as used by
+// YCSB, which supports exponents below 1. The constants are adjusted term by
+// term when the number of keys changes, the workers read them from an
+// immutable snapshot without locking.
+type zipfianChooser struct {
+	theta float64
+	alpha float64
+	zeta2 float64
+
+	current atomic.Pointer[zipfianConstants]
+	mu      sync.Mutex // serializes the updates of the constants
+}
+
+// zipfianConstants are the constants of the distribution for a number of keys
+type zipfianConstants struct {
+	numKeys int
+	zetaN   float64
+	eta     float64
+}
+
+func newZipfianChooser(numKeys int, theta float64) *zipfianChooser {
+	c := &zipfianChooser{
+		theta: theta,
+		alpha: 1 / (1 - theta),
+		zeta2: 1 + 1/math.Pow(2, theta),
+	}
+	c.current.Store(c.resize(&zipfianConstants{}, numKeys))
+	return c
+}
+
+// resize returns the constants of the distribution for the given number of
+// keys, derived from the constants for another number of keys
+func (c *zipfianChooser) resize(from *zipfianConstants, numKeys int) *zipfianConstants {
+	n, zetaN := from.numKeys, from.zetaN
+	for ; n < numKeys; n++ {
+		zetaN += 1 / math.Pow(float64(n+1), c.theta)
+	}
+	for ; n > numKeys; n-- {
+		zetaN -= 1 / math.Pow(float64(n), c.theta)
+	}
+	eta := (1 - math.Pow(2/float64(n), 1-c.theta)) / (1 - c.zeta2/zetaN)
+	return &zipfianConstants{numKeys: n, zetaN: zetaN, eta: eta}
+}
+
+// constants returns the constants of the distribution for the given number
+// of keys, the lock is only taken when the number of keys changed
+func (c *zipfianChooser) constants(numKeys int) *zipfianConstants {
+	if current := c.current.Load(); current.numKeys == numKeys {
+		return current
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	current := c.current.Load()
+	if current.numKeys != numKeys {
+		current = c.resize(current, numKeys)
+		c.current.Store(current)
+	}
+	return current
+}
+
+func (c *zipfianChooser) Next(rg *rand.Rand, numKeys int) int {
+	zc := c.constants(numKeys)
+
+	u := rg.Float64()
+	uz := u * zc.zetaN
+	if uz < 1 {
+		return 0
+	}
+	if uz < 1+math.Pow(0.5, c.theta) {
+		return min(1, numKeys-1)
+	}
+	index := int(float64(numKeys) * math.Pow(zc.eta*u-zc.eta+1, c.alpha))
+	return min(index, numKeys-1)
+}
+
+// scrambledZipfianChooser picks the keys following a zipfian distribution,
+// but spreads the popular keys over the key space by hashing the rank drawn
+// from the zipfian distribution, like the ScrambledZipfianGenerator of YCSB.
+// Otherwise the hot keys would be the first keys in sort order, which share
+// a prefix and thereby a range of the key space.
+type scrambledZipfianChooser struct {
+	zipfian *zipfianChooser
+}
+
+func (c *scrambledZipfianChooser) Next(rg *rand.Rand, numKeys int) int {
+	return int(fnvHash64(uint64(c.zipfian.Next(rg, numKeys))) % uint64(numKeys))
+}
+
+// fnvHash64 returns the 64 bit FNV-1a hash of the bytes of the value, as
+// computed by YCSB to scramble the zipfian ranks
+func fnvHash64(value uint64) uint64 {
+	hash := uint64(0xCBF29CE484222325)
+	for i := 0; i < 8; i++ {
+		hash ^= value & 0xff
+		hash *= 0x100000001B3
+		value >>= 8
+	}
+	return hash
+}
+
+// latestChooser picks the keys following a zipfian distribution, the last
+// key, i.e. the most recently inserted one, is the most popular one
+type latestChooser struct {
+	zipfian *zipfianChooser
+}
+
+func (c *latestChooser) Next(rg *rand.Rand, numKeys int) int {
+	return numKeys - 1 - c.zipfian.Next(rg, numKeys)
+}
+
+// hotspotChooser sends a share of the operations to the first keys, the
+// hot keys, and the other operations to the remaining keys
+type hotspotChooser struct {
+	hotKeyRate float64
+	hotOpRate  float64
+}
+
+func (c *hotspotChooser) Next(rg *rand.Rand, numKeys int) int {
+	hotKeys := max(int(float64(numKeys)*c.hotKeyRate), 1)
+	if rg.Float64() < c.hotOpRate || hotKeys >= numKeys {
+		return rg.Intn(min(hotKeys, numKeys))
+	}
+	return hotKeys + rg.Intn(numKeys-hotKeys)
+}
+
+// sequentialChooser walks through the keys in order and starts over at the
+// first key after the last one
+type sequentialChooser struct {
+	next int
+}
+
+func (c *sequentialChooser) Next(rg *rand.Rand, numKeys int) int {
+	index := c.next % numKeys
+	c.next = index + 1
+	return index
+}
@@ -0,0 +1,110 @@
+package runner
+
+import (
+	"csb/control/constants"
+	"math"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func TestZipfianChooserResize(t *testing.T) {
+	c := newZipfianChooser(100, 0.99)
+	want := newZipfianChooser(1000, 0.99).current.Load()
+
+	// Test that the constants derived term by term match the ones computed
+	// for the number of keys, whichever size they were derived from
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, numKeys := range []int{500, 1000, 2000, 1000} {
+				c.constants(numKeys)
+			}
+		}()
+	}
+	wg.Wait()
+	got := c.constants(1000)
+	if got.numKeys != 1000 || math.Abs(got.zetaN-want.zetaN) > 1e-9 || math.Abs(got.eta-want.eta) > 1e-9 {
+		t.Errorf("constants(1000) = %+v, want %+v", *got, *want)
+	}
+	if c.constants(1000) != got {
+		t.Error("constants() replaced the snapshot while the number of keys did not change")
+	}
+}
+
+func TestLatestChooserFavoursNewestKeys(t *testing.T) {
+	chooser := &latestChooser{zipfian: newZipfianChooser(1000, 0.99)}
+	rg := rand.New(rand.NewSource(1))
+
+	// Test that the most recently inserted key, the one with the highest
+	// index, is picked most often, also after the key space grew
+	for _, numKeys := range []int{1000, 1100} {
+		counts := make([]int, numKeys)
+		for i := 0; i < 100000; i++ {
+			index := chooser.Next(rg, numKeys)
+			if index < 0 || index >= numKeys {
+				t.Fatalf("Next() = %d, want an index below %d", index, numKeys)
+			}
+			counts[index]++
+		}
+		top := 0
+		for i, count := range counts {
+			if count > counts[top] {
+				top = i
+			}
+		}
+		if top != numKeys-1 {
+			t.Errorf("most picked index = %d with %d keys, want %d", top, numKeys, numKeys-1)
+		}
+		newest := 0
+		for _, count := range counts[numKeys-numKeys/10:] {
+			newest += count
+		}
+		if newest < 50000 {
+			t.Errorf("newest tenth of %d keys picked %d times out of 100000, want the majority", numKeys, newest)
+		}
+	}
+}
+
+func TestYCSBScrambledZipfianChooser(t *testing.T) {
+	config := &BenchmarkRunConfig{}
+	config.WorkloadType = constants.WORKLOAD_TYPE_YCSB_A
+	config.ZipfianTheta = 0
+	newChooser, err := newKeyChooserFactory(config, 1000)
+	if err != nil {
+		t.Fatalf("newKeyChooserFactory() error = %v", err)
+	}
+	chooser, ok := newChooser(0).(*scrambledZipfianChooser)
+	if !ok {
+		t.Fatalf("key chooser of %s = %T, want a scrambled zipfian chooser", config.WorkloadType, newChooser(0))
+	}
+	if chooser.zipfian.theta != ycsbZipfianTheta {
+		t.Errorf("theta = %v, want the YCSB constant %v", chooser.zipfian.theta, ycsbZipfianTheta)
+	}
+
+	// Test that the picks stay skewed, but the most popular keys are not
+	// the first keys in sort order
+	rg := rand.New(rand.NewSource(1))
+	counts := make([]int, 1000)
+	for i := 0; i < 100000; i++ {
+		counts[chooser.Next(rg, 1000)]++
+	}
+	first := 0
+	for _, count := range counts[:100] {
+		first += count
+	}
+	if first > 30000 {
+		t.Errorf("first tenth of the keys picked %d times out of 100000, want the popular keys spread", first)
+	}
+	top := 0
+	for i, count := range counts {
+		if count > counts[top] {
+			top = i
+		}
+	}
+	if counts[top] < 5000 {
+		t.Errorf("most picked key picked %d times out of 100000, want a skewed distribution", counts[top])
+	}
+}
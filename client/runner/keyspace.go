@@ -0,0 +1,198 @@
+package runner
+
+import (
+	generator "csb/data-generator"
+	"errors"
+	"math/rand"
+	"sync"
+)
+
+// errKeySpaceExhausted is returned by deletes which would remove the last key
+var errKeySpaceExhausted = errors.New("cannot delete the last key of the key space")
+
+// States of the keys of the key space
+const (
+	keyLive     = iota
+	keyDeleting // the key is being deleted from the database
+	keyDeleted  // the key was deleted, its slot is dropped by the next compaction
+)
+
+// keySlot is the position of a key in the key space
+type keySlot struct {
+	key   string
+	state int
+}
+
+// KeySpace is the set of live keys shared by the workers of a benchmark
+// client, it starts with the loaded keys and grows and shrinks with inserts
+// and deletes. The keys keep the order they were inserted in, so the most
+// recently inserted key has the highest index. A deleted key leaves a hole,
+// an index which points at a hole picks the next live key after it. The
+// holes are compacted once they make up a quarter of the key space. Keys
+// inserted or deleted by other benchmark clients are not known.
+type KeySpace struct {
+	mu        sync.RWMutex
+	slots     []keySlot
+	index     map[string]int // position of every key which was not deleted in slots
+	keySize   int
+	available int // number of live keys which are not being deleted
+	deleting  int // number of keys being deleted
+	holes     int // number of slots of deleted keys
+	inserted  int64
+	deleted   int64
+}
+
+func NewKeySpace(keys []string, keySize int) *KeySpace {
+	ks := &KeySpace{
+		slots:     make([]keySlot, len(keys)),
+		index:     make(map[string]int, len(keys)),
+		keySize:   keySize,
+		available: len(keys),
+	}
+	for i, key := range keys {
+		ks.slots[i] = keySlot{key: key}
+		ks.index[key] = i
+	}
+	return ks
+}
+
+// Len returns the number of indices of the key space, which the key choosers
+// pick from. The holes of deleted keys count until they are compacted.
+func (ks *KeySpace) Len() int {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	return len(ks.slots)
+}
+
+// Key returns the key at the given index, or the next live key after it if
+// the key at the index is deleted. Indices beyond the key space wrap around
+// as the key space may shrink between picking and reading an index.
+func (ks *KeySpace) Key(i int) string {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	return ks.slots[ks.liveSlot(i)].key
+}
+
+// First returns up to n live keys from the start of the key space, the keys
+// which were loaded first unless they were deleted
+func (ks *KeySpace) First(n int) []string {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	keys := make([]string, 0, n)
+	for _, slot := range ks.slots {
+		if len(keys) == n {
+			break
+		}
+		if slot.state == keyLive {
+			keys = append(keys, slot.key)
+		}
+	}
+	return keys
+}
+
+// liveSlot returns the position of the first live key at or after the index,
+// the position of the index if all keys are being deleted
+func (ks *KeySpace) liveSlot(i int) int {
+	n := len(ks.slots)
+	i %= n
+	for j := 0; j < n; j++ {
+		if pos := (i + j) % n; ks.slots[pos].state == keyLive {
+			return pos
+		}
+	}
+	return i
+}
+
+// Stats returns the number of live, inserted and deleted keys, keys which
+// are being deleted are still live
+func (ks *KeySpace) Stats() (live int, inserted int64, deleted int64) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	return ks.available + ks.deleting, ks.inserted, ks.deleted
+}
+
+// NewKey generates a key which is not part of the key space from the random
+// generator of a worker, so that the keys only depend on the seed and the
+// client ID
+func (ks *KeySpace) NewKey(rg *rand.Rand) (string, error) {
+	for {
+		key, err := generator.RandomKey(rg, ks.keySize)
+		if err != nil {
+			return "", err
+		}
+		ks.mu.RLock()
+		_, ok := ks.index[key]
+		ks.mu.RUnlock()
+		if !ok {
+			return key, nil
+		}
+	}
+}
+
+// Insert adds a key after it was written to the database
+func (ks *KeySpace) Insert(key string) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if _, ok := ks.index[key]; ok {
+		return
+	}
+	ks.index[key] = len(ks.slots)
+	ks.slots = append(ks.slots, keySlot{key: key})
+	ks.available++
+	ks.inserted++
+}
+
+// Delete marks the key at the given index, or the next live key after it,
+// as being deleted before it is deleted from the database, so that no other
+// worker picks it anymore. FinishDelete has to be called with the outcome of
+// the delete. The last key of the key space is never deleted.
+func (ks *KeySpace) Delete(i int) (string, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if ks.available <= 1 {
+		return "", errKeySpaceExhausted
+	}
+	pos := ks.liveSlot(i)
+	ks.slots[pos].state = keyDeleting
+	ks.available--
+	ks.deleting++
+	return ks.slots[pos].key, nil
+}
+
+// FinishDelete removes a key marked by Delete from the key space once it was
+// deleted from the database, otherwise the key is live again at its position
+func (ks *KeySpace) FinishDelete(key string, deleted bool) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	pos, ok := ks.index[key]
+	if !ok || ks.slots[pos].state != keyDeleting {
+		return
+	}
+	ks.deleting--
+	if !deleted {
+		ks.slots[pos].state = keyLive
+		ks.available++
+		return
+	}
+	ks.slots[pos].state = keyDeleted
+	delete(ks.index, key)
+	ks.holes++
+	ks.deleted++
+	if ks.holes*4 >= len(ks.slots) {
+		ks.compact()
+	}
+}
+
+// compact drops the slots of the deleted keys, the other keys keep their order
+func (ks *KeySpace) compact() {
+	slots := make([]keySlot, 0, len(ks.slots)-ks.holes)
+	for _, slot := range ks.slots {
+		if slot.state == keyDeleted {
+			continue
+		}
+		ks.index[slot.key] = len(slots)
+		slots = append(slots, slot)
+	}
+	ks.slots = slots
+	ks.holes = 0
+}
@@ -0,0 +1,131 @@
+package runner
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func keySpaceKeys(ks *KeySpace) []string {
+	keys := make([]string, 0, ks.Len())
+	for i := 0; i < ks.Len(); i++ {
+		if key := ks.Key(i); len(keys) == 0 || keys[len(keys)-1] != key {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func TestKeySpaceKeepsInsertionOrder(t *testing.T) {
+	ks := NewKeySpace([]string{"a", "b", "c", "d", "e", "f", "g", "h"}, 16)
+	ks.Insert("i")
+
+	// Test that a deleted key is skipped and the newest key keeps the
+	// highest index
+	key, err := ks.Delete(1)
+	if err != nil || key != "b" {
+		t.Fatalf("Delete() = %q, %v, want b", key, err)
+	}
+	if got := ks.Key(1); got != "c" {
+		t.Errorf("Key(1) = %q while b is deleted, want c", got)
+	}
+	ks.FinishDelete(key, true)
+	if got := ks.Key(ks.Len() - 1); got != "i" {
+		t.Errorf("Key(Len()-1) = %q, want newest key i", got)
+	}
+
+	// Test that the compaction keeps the order of the keys
+	for _, i := range []int{3, 5} {
+		key, err := ks.Delete(i)
+		if err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		ks.FinishDelete(key, true)
+	}
+	if ks.Len() != 6 {
+		t.Errorf("Len() = %d after compaction, want 6", ks.Len())
+	}
+	want := []string{"a", "c", "e", "g", "h", "i"}
+	for i, key := range want {
+		if got := ks.Key(i); got != key {
+			t.Errorf("Key(%d) = %q, want %q", i, got, key)
+		}
+	}
+	if live, inserted, deleted := ks.Stats(); live != 6 || inserted != 1 || deleted != 3 {
+		t.Errorf("Stats() = %d, %d, %d, want 6, 1, 3", live, inserted, deleted)
+	}
+}
+
+func TestKeySpaceFirstSkipsDeletedKeys(t *testing.T) {
+	ks := NewKeySpace([]string{"a", "b", "c", "d"}, 16)
+	deleted, err := ks.Delete(0)
+	if err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	ks.FinishDelete(deleted, true)
+	// the deleted key was compacted away, so c is at index 1
+	deleting, err := ks.Delete(1)
+	if err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	// Test that neither deleted keys nor keys being deleted are returned
+	want := []string{"b", "d"}
+	if got := ks.First(3); len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("First(3) = %q while %s is being deleted, want %q", got, deleting, want)
+	}
+	ks.FinishDelete(deleting, false)
+	if got := ks.First(2); len(got) != 2 || got[0] != "b" || got[1] != "c" {
+		t.Errorf("First(2) = %q, want [b c]", got)
+	}
+}
+
+func TestKeySpaceFailedDelete(t *testing.T) {
+	ks := NewKeySpace([]string{"a", "b", "c"}, 16)
+	key, err := ks.Delete(2)
+	if err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if live, _, _ := ks.Stats(); live != 3 {
+		t.Errorf("Stats() live = %d while the key is being deleted, want 3", live)
+	}
+
+	// Test that the key is back at its position after a failed delete
+	ks.FinishDelete(key, false)
+	if got := keySpaceKeys(ks); len(got) != 3 || got[2] != "c" {
+		t.Errorf("keys = %v after failed delete, want [a b c]", got)
+	}
+	if _, _, deleted := ks.Stats(); deleted != 0 {
+		t.Errorf("Stats() deleted = %d, want 0", deleted)
+	}
+
+	// Test that the last key is never deleted
+	for i := 0; i < 2; i++ {
+		key, err := ks.Delete(0)
+		if err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		ks.FinishDelete(key, true)
+	}
+	if _, err := ks.Delete(0); err != errKeySpaceExhausted {
+		t.Errorf("Delete() error = %v, want %v", err, errKeySpaceExhausted)
+	}
+}
+
+func TestKeySpaceNewKeyDeterminism(t *testing.T) {
+	ks1 := NewKeySpace([]string{"a"}, 16)
+	ks2 := NewKeySpace([]string{"a"}, 16)
+	rg1 := rand.New(rand.NewSource(7))
+	rg2 := rand.New(rand.NewSource(7))
+	for i := 0; i < 10; i++ {
+		key1, err := ks1.NewKey(rg1)
+		if err != nil {
+			t.Fatalf("NewKey() error = %v", err)
+		}
+		key2, _ := ks2.NewKey(rg2)
+		if key1 != key2 {
+			t.Errorf("NewKey() = %q and %q with the same random generator", key1, key2)
+		}
+		ks1.Insert(key1)
+		ks2.Insert(key2)
+	}
+}
@@ -0,0 +1,99 @@
+package runner
+
+import (
+	benchCfg "csb/control/config"
+	"csb/control/constants"
+	"fmt"
+	"time"
+)
+
+// loadProfile decides on the load of the main benchmark steps. The load level
+// of a step is the number of clients in closed-loop mode and the target rate
+// in operations per second in open-loop mode.
+type loadProfile interface {
+	// next returns the next step, false if the profile has no further step
+	next() (benchCfg.LoadStep, bool)
+	// observe records the result of the last step
+	observe(step benchCfg.LoadStep, result *StepResult)
+}
+
+func newLoadProfile(config *BenchmarkRunConfig) (loadProfile, error) {
+	initial, stepSize, maxLevel := config.InitialClients, config.ClientStepSize, config.MaxClients
+	if config.LoopMode == constants.LOOP_MODE_OPEN {
+		initial, stepSize, maxLevel = config.InitialRate, config.RateStepSize, config.MaxRate
+	}
+	stepDuration := time.Duration(config.StepDuration)
+
+	switch config.LoadMode {
+	case constants.LOAD_MODE_CONSTANT:
+		return &constantProfile{step: benchCfg.LoadStep{Level: initial, Duration: stepDuration}}, nil
+	case constants.LOAD_MODE_RAMP_UP_DOWN:
+		return &rampUpDownProfile{
+			level:        initial,
+			stepSize:     stepSize,
+			minLevel:     initial,
+			maxLevel:     maxLevel,
+			stepDuration: stepDuration,
+		}, nil
+	case constants.LOAD_MODE_STEPS, constants.LOAD_MODE_CSV:
+		steps, err := benchCfg.ParseLoadSchedule(config.LoadSchedule)
+		if err != nil {
+			return nil, err
+		}
+		if len(steps) == 0 {
+			return nil, fmt.Errorf("load mode %s requires a load schedule", config.LoadMode)
+		}
+		for i := range steps {
+			if steps[i].Duration == 0 {
+				steps[i].Duration = stepDuration
+			}
+		}
+		return &scheduleProfile{steps: steps}, nil
+	case constants.LOAD_MODE_SPIKE:
+		return &spikeProfile{
+			base:  benchCfg.LoadStep{Level: initial, Duration: time.Duration(config.SpikeInterval - config.SpikeDuration)},
+			spike: benchCfg.LoadStep{Level: maxLevel, Duration: time.Duration(config.SpikeDuration)},
+		}, nil
+	case constants.LOAD_MODE_SINE:
+		return &sineProfile{
+			minLevel:     initial,
+			maxLevel:     maxLevel,
+			period:       time.Duration(config.SinePeriod),
+			stepDuration: stepDuration,
+		}, nil
+	case constants.LOAD_MODE_SLA_SEARCH:
+		return &capacitySearch{
+			slaLatency:    time.Duration(config.SLALatency),
+			slaPercentile: config.SLAPercentile,
+			maxErrorRate:  config.SLAMaxErrorRate,
+			initial:       initial,
+			resolution:    stepSize,
+			maxLevel:      maxLevel,
+			stepDuration:  stepDuration,
+		}, nil
+	default:
+		return &rampProfile{
+			level:        initial,
+			stepSize:     stepSize,
+			maxLevel:     maxLevel,
+			stepDuration: stepDuration,
+		}, nil
+	}
+}
+
+// stepLoad translates a load level into the number of clients and the target
+// rate of a step, the rate is 0 in closed-loop mode
+func stepLoad(config *BenchmarkRunConfig, level int) (numClients int, rate int) {
+	if config.LoopMode == constants.LOOP_MODE_OPEN {
+		return config.OpenLoopWorkers, level
+	}
+	return level, 0
+}
+
+// describeLoad formats the load of a step for status messages
+func describeLoad(numClients int, rate int) string {
+	if rate > 0 {
+		return fmt.Sprintf("%d ops/s target rate (%d workers)", rate, numClients)
+	}
+	return fmt.Sprintf("%d clients", numClients)
+}
@@ -0,0 +1,75 @@
+package runner
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// requestFunc issues a single request of a worker, start is the instant the
+// latency of the request is measured from
+type requestFunc func(start time.Time)
+
+// runWorkers runs the workers of a load step until the context is done.
+// With a rate of 0 the workers run in a closed loop, each of them issues its
+// next request as soon as the previous one returned. Otherwise the requests
+// are dispatched at the given rate to the pool of workers and the latency is
+// measured from the intended send time, so that the time a request waits
+// for a free worker is not hidden from the results.
+func runWorkers(ctx context.Context, numWorkers int, rate int, newWorker func(workerID int) requestFunc) {
+	var schedule chan time.Time
+	if rate > 0 {
+		schedule = make(chan time.Time, numWorkers)
+		go dispatchRequests(ctx, rate, schedule)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func(workerID int) {
+			defer wg.Done()
+			request := newWorker(workerID)
+			if schedule == nil {
+				for ctx.Err() == nil {
+					request(time.Now())
+				}
+				return
+			}
+			for start := range schedule {
+				if ctx.Err() != nil {
+					return
+				}
+				request(start)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+// dispatchRequests sends the intended send times of the requests to the
+// workers until the context is done. The send times are derived from the
+// start of the dispatch rather than from the previous request, so that the
+// rate does not drift when the workers fall behind.
+func dispatchRequests(ctx context.Context, rate int, schedule chan<- time.Time) {
+	defer close(schedule)
+	interval := time.Second / time.Duration(rate)
+	begin := time.Now()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for i := 0; ; i++ {
+		next := begin.Add(time.Duration(i) * interval)
+		if wait := time.Until(next); wait > 0 {
+			timer.Reset(wait)
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case schedule <- next:
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package runner
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDispatchRequestsKeepsSchedule(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	schedule := make(chan time.Time)
+	go dispatchRequests(ctx, 1000, schedule)
+
+	// Test that the send times follow the rate even if the requests are
+	// taken late
+	var times []time.Time
+	for start := range schedule {
+		times = append(times, start)
+		time.Sleep(3 * time.Millisecond)
+	}
+	if len(times) < 2 {
+		t.Fatalf("dispatched %d requests, want several", len(times))
+	}
+	for i := 1; i < len(times); i++ {
+		if gap := times[i].Sub(times[i-1]); gap != time.Millisecond {
+			t.Fatalf("gap between send time %d and %d = %v, want 1ms", i-1, i, gap)
+		}
+	}
+}
+
+func TestRunWorkersOpenLoop(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	// A single worker whose requests take three times the interval of the
+	// rate falls behind the schedule
+	var mu sync.Mutex
+	var starts, issued []time.Time
+	runWorkers(ctx, 1, 100, func(int) requestFunc {
+		return func(start time.Time) {
+			mu.Lock()
+			starts = append(starts, start)
+			issued = append(issued, time.Now())
+			mu.Unlock()
+			time.Sleep(30 * time.Millisecond)
+		}
+	})
+
+	if len(starts) < 3 {
+		t.Fatalf("issued %d requests, want several", len(starts))
+	}
+	// Test that the latencies are measured from the intended send times
+	// rather than from the instant the worker got to the request, so that
+	// the waiting time is not omitted
+	for i := 1; i < len(starts); i++ {
+		if gap := starts[i].Sub(starts[i-1]); gap != 10*time.Millisecond {
+			t.Fatalf("gap between intended send time %d and %d = %v, want 10ms", i-1, i, gap)
+		}
+	}
+	last := len(starts) - 1
+	if lag := issued[last].Sub(starts[last]); lag < 20*time.Millisecond*time.Duration(last) {
+		t.Errorf("request %d issued %v after its intended send time, want at least %v", last, lag, 20*time.Millisecond*time.Duration(last))
+	}
+}
+
+func TestRunWorkersClosedLoop(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// Test that every worker measures from the instant it issues a request
+	var mu sync.Mutex
+	requests := make(map[int]int)
+	runWorkers(ctx, 4, 0, func(workerID int) requestFunc {
+		return func(start time.Time) {
+			if lag := time.Since(start); lag > 20*time.Millisecond {
+				t.Errorf("request of worker %d issued %v after its start", workerID, lag)
+			}
+			mu.Lock()
+			requests[workerID]++
+			mu.Unlock()
+			time.Sleep(time.Millisecond)
+		}
+	})
+	if len(requests) != 4 {
+		t.Errorf("%d workers issued requests, want 4", len(requests))
+	}
+}
@@ -1,25 +1,67 @@
 package runner
 
 import (
+	"csb/control/constants"
 	"encoding/csv"
+	"fmt"
 	"os"
 	"strconv"
 	"sync"
 	"time"
 )
 
+// Version of the metrics file format, it is written in the first line of the
+// file. Version 2 records the latencies in microseconds, files without the
+// marker record them in milliseconds.
+const metricsFormatVersion = 2
+
 // StepResult holds metrics for each load step
 type RequestMetric struct {
 	Timestamp  time.Time     // Timestamp of the request
 	Key        string        // Key being accessed
 	Operation  string        // "read" or "write"
-	Latency    time.Duration // Latency in milliseconds
+	Latency    time.Duration // Latency of the operation
 	Success    bool          // Whether the operation succeeded
 	StatusCode int           // etcd response status code
 	StatusText string        // etcd response status text
 	NumClients int           // Number of clients at current step
 	ClientID   int           // ID of the client that made the request
 	RunPhase   string        // Phase of the run
+	Err        error         // Error of the operation, not exported
+}
+
+// KVMetric extends RequestMetric with the size of the response of kv-store
+// operations and the attempts of transactions
+type KVMetric struct {
+	*RequestMetric
+	KeysScanned   int64  // Number of keys returned, counted or deleted by the operation
+	RequestBytes  int64  // Size of the keys and values written by the operation in bytes
+	ResponseBytes int64  // Size of the responses of the operation in bytes
+	TxnKeys       int    // Number of keys updated by the transaction
+	Conflicts     int    // Number of attempts which failed the compare of the transaction
+	Retries       int    // Number of attempts after the first one
+	Consistency   string // Read consistency of reads, empty for writes
+	Revision      int64  // Oldest past revision read by the operation, 0 for the latest revision
+	RevisionLag   int64  // Revisions between the revision read and the latest known revision
+	SizeClass     string // Size class of the value of large objects, not exported
+}
+
+// WatchMetric extends RequestMetric for the events received by watchers
+type WatchMetric struct {
+	*RequestMetric
+	WatchTarget string // Key or prefix watched by the watcher
+	Revision    int64  // Mod revision of the event
+}
+
+// LeaseMetric extends RequestMetric for lease operations
+type LeaseMetric struct {
+	*RequestMetric
+	LeaseID          int64         // ID of the lease
+	TTL              int64         // TTL of the lease in seconds granted by etcd
+	GrantLatency     time.Duration // Latency of the grant operation
+	KeepAliveLatency time.Duration // Latency of the keepalive operation
+	RevokeLatency    time.Duration // Latency of the revoke operation
+	ExpiryDelay      time.Duration // Time from the expiry of the lease to the deletion event of its key
 }
 
 // LockMetric extends RequestMetric for lock-specific operations
@@ -36,6 +78,7 @@ type LockMetric struct {
 type Metric interface {
 	ToCSVRow() []string // Converts the metric to a slice of strings for CSV writing
 	ToCSVHeader() []string
+	Request() *RequestMetric // Returns the fields shared by all metrics
 }
 
 // MetricsExporter handles the export of raw metrics to CSV
@@ -46,12 +89,16 @@ type MetricsExporter struct {
 	mu        sync.Mutex
 }
 
+func (m *RequestMetric) Request() *RequestMetric {
+	return m
+}
+
 func (m *RequestMetric) ToCSVRow() []string {
 	return []string{
 		strconv.FormatInt(m.Timestamp.UnixNano(), 10),
 		m.Key,
 		m.Operation,
-		strconv.FormatInt(m.Latency.Milliseconds(), 10),
+		strconv.FormatInt(m.Latency.Microseconds(), 10),
 		strconv.FormatBool(m.Success),
 		strconv.Itoa(m.StatusCode),
 		m.StatusText,
@@ -66,7 +113,7 @@ func (m *RequestMetric) ToCSVHeader() []string {
 		"unix_timestamp_nano",
 		"key",
 		"operation",
-		"latency_ms",
+		"latency_us",
 		"success",
 		"status_code",
 		"status_text",
@@ -76,12 +123,82 @@ func (m *RequestMetric) ToCSVHeader() []string {
 	}
 }
 
+func (m *KVMetric) ToCSVHeader() []string {
+	return append(
+		m.RequestMetric.ToCSVHeader(),
+		"keys_scanned",
+		"request_bytes",
+		"response_bytes",
+		"txn_keys",
+		"conflicts",
+		"retries",
+		"consistency",
+		"revision",
+		"revision_lag",
+	)
+}
+
+func (m *KVMetric) ToCSVRow() []string {
+	return append(
+		m.RequestMetric.ToCSVRow(),
+		strconv.FormatInt(m.KeysScanned, 10),
+		strconv.FormatInt(m.RequestBytes, 10),
+		strconv.FormatInt(m.ResponseBytes, 10),
+		strconv.Itoa(m.TxnKeys),
+		strconv.Itoa(m.Conflicts),
+		strconv.Itoa(m.Retries),
+		m.Consistency,
+		strconv.FormatInt(m.Revision, 10),
+		strconv.FormatInt(m.RevisionLag, 10),
+	)
+}
+
+func (m *WatchMetric) ToCSVHeader() []string {
+	return append(
+		m.RequestMetric.ToCSVHeader(),
+		"watch_target",
+		"revision",
+	)
+}
+
+func (m *WatchMetric) ToCSVRow() []string {
+	return append(
+		m.RequestMetric.ToCSVRow(),
+		m.WatchTarget,
+		strconv.FormatInt(m.Revision, 10),
+	)
+}
+
+func (m *LeaseMetric) ToCSVHeader() []string {
+	return append(
+		m.RequestMetric.ToCSVHeader(),
+		"lease_id",
+		"ttl_s",
+		"grant_latency_us",
+		"keepalive_latency_us",
+		"revoke_latency_us",
+		"expiry_delay_us",
+	)
+}
+
+func (m *LeaseMetric) ToCSVRow() []string {
+	return append(
+		m.RequestMetric.ToCSVRow(),
+		strconv.FormatInt(m.LeaseID, 10),
+		strconv.FormatInt(m.TTL, 10),
+		strconv.FormatInt(m.GrantLatency.Microseconds(), 10),
+		strconv.FormatInt(m.KeepAliveLatency.Microseconds(), 10),
+		strconv.FormatInt(m.RevokeLatency.Microseconds(), 10),
+		strconv.FormatInt(m.ExpiryDelay.Microseconds(), 10),
+	)
+}
+
 func (m *LockMetric) ToCSVHeader() []string {
 	return append(
 		m.RequestMetric.ToCSVHeader(),
 		"lock_name",
-		"aquire_latency_ms",
-		"release_latency_ms",
+		"aquire_latency_us",
+		"release_latency_us",
 		"lock_op_status_code",
 		"lock_op_status_text",
 		"contention_level",
@@ -92,8 +209,8 @@ func (m *LockMetric) ToCSVRow() []string {
 	return append(
 		m.RequestMetric.ToCSVRow(),
 		m.LockName,
-		strconv.FormatInt(m.AquireLatency.Milliseconds(), 10),
-		strconv.FormatInt(m.ReleaseLatency.Milliseconds(), 10),
+		strconv.FormatInt(m.AquireLatency.Microseconds(), 10),
+		strconv.FormatInt(m.ReleaseLatency.Microseconds(), 10),
 		strconv.Itoa(m.LockOpStatusCode),
 		m.LockOpStatusText,
 		strconv.Itoa(m.ContentionLevel),
@@ -106,7 +223,11 @@ func NewMetricsExporter(filename string, batchSize int, header []string) (*Metri
 		return nil, err
 	}
 
-	// Write CSV header
+	// Write the format version marker and the CSV header
+	if _, err := fmt.Fprintf(file, "%s%d\n", constants.METRICS_FORMAT_MARKER, metricsFormatVersion); err != nil {
+		file.Close()
+		return nil, err
+	}
 	writer := csv.NewWriter(file)
 	err = writer.Write(header)
 	if err != nil {
@@ -0,0 +1,70 @@
+package runner
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"csb/control/constants"
+)
+
+// pacer paces the requests of a worker in closed-loop mode, the worker
+// pauses for a think time after each request and starts at most one request
+// per interval
+type pacer struct {
+	thinkTime    time.Duration
+	distribution string
+	interval     time.Duration // minimum time between the starts of two requests, 0 for no limit
+	rand         *rand.Rand
+}
+
+// newPacer returns the pacer of a worker, nil if the workers are not paced
+func newPacer(config *BenchmarkRunConfig, rg *rand.Rand) *pacer {
+	if config.LoopMode == constants.LOOP_MODE_OPEN || (config.ThinkTime <= 0 && config.ClientRateLimit <= 0) {
+		return nil
+	}
+	p := &pacer{
+		thinkTime:    time.Duration(config.ThinkTime),
+		distribution: config.ThinkTimeDistribution,
+		rand:         rg,
+	}
+	if config.ClientRateLimit > 0 {
+		p.interval = time.Duration(float64(time.Second) / config.ClientRateLimit)
+	}
+	return p
+}
+
+// think returns the pause after a request
+func (p *pacer) think() time.Duration {
+	if p.thinkTime <= 0 {
+		return 0
+	}
+	switch p.distribution {
+	case constants.THINK_TIME_EXPONENTIAL:
+		return time.Duration(p.rand.ExpFloat64() * float64(p.thinkTime))
+	case constants.THINK_TIME_UNIFORM:
+		return time.Duration(p.rand.Int63n(2*int64(p.thinkTime) + 1))
+	default:
+		return p.thinkTime
+	}
+}
+
+// wait blocks until the worker may start its next request, start is the
+// instant the previous request started. It returns early once the context
+// is done.
+func (p *pacer) wait(ctx context.Context, start time.Time) {
+	next := time.Now().Add(p.think())
+	if limit := start.Add(p.interval); limit.After(next) {
+		next = limit
+	}
+	wait := time.Until(next)
+	if wait <= 0 {
+		return
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
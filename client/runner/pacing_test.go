@@ -0,0 +1,85 @@
+package runner
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	benchCfg "csb/control/config"
+	"csb/control/constants"
+)
+
+func TestNewPacer(t *testing.T) {
+	cfg := &BenchmarkRunConfig{BenchctlConfig: *benchCfg.GetDefaultConfig()}
+	cfg.ThinkTime, cfg.ClientRateLimit = 0, 0
+	if p := newPacer(cfg, nil); p != nil {
+		t.Error("newPacer() returned a pacer without think time and rate limit")
+	}
+	cfg.ClientRateLimit = 50
+	p := newPacer(cfg, rand.New(rand.NewSource(1)))
+	if p == nil || p.interval != 20*time.Millisecond {
+		t.Fatalf("newPacer() = %+v, want an interval of 20ms", p)
+	}
+	cfg.LoopMode = constants.LOOP_MODE_OPEN
+	if p := newPacer(cfg, nil); p != nil {
+		t.Error("newPacer() returned a pacer in open-loop mode")
+	}
+}
+
+func TestPacerThinkTime(t *testing.T) {
+	thinkTime := 10 * time.Millisecond
+	tests := []struct {
+		distribution string
+		maxPause     time.Duration
+	}{
+		{constants.THINK_TIME_FIXED, thinkTime},
+		{constants.THINK_TIME_UNIFORM, 2 * thinkTime},
+		{constants.THINK_TIME_EXPONENTIAL, time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.distribution, func(t *testing.T) {
+			p := &pacer{thinkTime: thinkTime, distribution: tt.distribution, rand: rand.New(rand.NewSource(1))}
+			var total time.Duration
+			const n = 10000
+			for i := 0; i < n; i++ {
+				pause := p.think()
+				if pause < 0 || pause > tt.maxPause {
+					t.Fatalf("think() = %v, want between 0 and %v", pause, tt.maxPause)
+				}
+				total += pause
+			}
+			// Test that the mean pause is the think time
+			if mean := total / n; mean < 9*time.Millisecond || mean > 11*time.Millisecond {
+				t.Errorf("mean pause = %v, want about %v", mean, thinkTime)
+			}
+		})
+	}
+}
+
+func TestPacerWait(t *testing.T) {
+	p := &pacer{interval: 20 * time.Millisecond, distribution: constants.THINK_TIME_FIXED}
+
+	// Test that the rate limit counts from the start of the previous request
+	start := time.Now()
+	p.wait(context.Background(), start)
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("wait() returned after %v, want at least the interval of 20ms", elapsed)
+	}
+	start = time.Now()
+	p.wait(context.Background(), start.Add(-time.Second))
+	if elapsed := time.Since(start); elapsed > 5*time.Millisecond {
+		t.Errorf("wait() blocked for %v after a request which took longer than the interval", elapsed)
+	}
+
+	// Test that the wait ends with the context
+	p.thinkTime = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start = time.Now()
+	p.wait(ctx, start)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("wait() blocked for %v with a done context", elapsed)
+	}
+}
@@ -0,0 +1,118 @@
+package runner
+
+import (
+	benchCfg "csb/control/config"
+	"math"
+	"time"
+)
+
+// constantProfile keeps the initial load level for the total duration
+type constantProfile struct {
+	step benchCfg.LoadStep
+}
+
+func (p *constantProfile) next() (benchCfg.LoadStep, bool) {
+	return p.step, true
+}
+
+func (p *constantProfile) observe(benchCfg.LoadStep, *StepResult) {}
+
+// rampProfile increases the load level after every step until the maximum
+// level is reached, the steps continue until the total duration is over
+type rampProfile struct {
+	level        int
+	stepSize     int
+	maxLevel     int
+	stepDuration time.Duration
+}
+
+func (p *rampProfile) next() (benchCfg.LoadStep, bool) {
+	return benchCfg.LoadStep{Level: p.level, Duration: p.stepDuration}, true
+}
+
+func (p *rampProfile) observe(benchCfg.LoadStep, *StepResult) {
+	p.level = min(p.level+p.stepSize, p.maxLevel)
+}
+
+// rampUpDownProfile increases the load level up to the maximum level and
+// decreases it back to the initial level, over and over again
+type rampUpDownProfile struct {
+	level        int
+	stepSize     int
+	minLevel     int
+	maxLevel     int
+	stepDuration time.Duration
+	down         bool
+}
+
+func (p *rampUpDownProfile) next() (benchCfg.LoadStep, bool) {
+	return benchCfg.LoadStep{Level: p.level, Duration: p.stepDuration}, true
+}
+
+func (p *rampUpDownProfile) observe(benchCfg.LoadStep, *StepResult) {
+	if p.down {
+		p.level = max(p.level-p.stepSize, p.minLevel)
+		p.down = p.level > p.minLevel
+	} else {
+		p.level = min(p.level+p.stepSize, p.maxLevel)
+		p.down = p.level >= p.maxLevel
+	}
+}
+
+// scheduleProfile runs the steps of a load schedule once, the run ends with
+// the last step of the schedule
+type scheduleProfile struct {
+	steps []benchCfg.LoadStep
+	index int
+}
+
+func (p *scheduleProfile) next() (benchCfg.LoadStep, bool) {
+	if p.index >= len(p.steps) {
+		return benchCfg.LoadStep{}, false
+	}
+	return p.steps[p.index], true
+}
+
+func (p *scheduleProfile) observe(benchCfg.LoadStep, *StepResult) {
+	p.index++
+}
+
+// spikeProfile keeps the initial load level and bursts to the maximum level
+// for the spike duration once per spike interval
+type spikeProfile struct {
+	base    benchCfg.LoadStep
+	spike   benchCfg.LoadStep
+	inSpike bool
+}
+
+func (p *spikeProfile) next() (benchCfg.LoadStep, bool) {
+	if p.inSpike {
+		return p.spike, true
+	}
+	return p.base, true
+}
+
+func (p *spikeProfile) observe(benchCfg.LoadStep, *StepResult) {
+	p.inSpike = !p.inSpike
+}
+
+// sineProfile moves the load level between the initial and the maximum level
+// along a sine wave, the level is sampled at the start of every step
+type sineProfile struct {
+	minLevel     int
+	maxLevel     int
+	period       time.Duration
+	stepDuration time.Duration
+	elapsed      time.Duration
+}
+
+func (p *sineProfile) next() (benchCfg.LoadStep, bool) {
+	phase := 2 * math.Pi * float64(p.elapsed) / float64(p.period)
+	amplitude := float64(p.maxLevel-p.minLevel) * (1 - math.Cos(phase)) / 2
+	level := max(p.minLevel+int(math.Round(amplitude)), 1)
+	return benchCfg.LoadStep{Level: level, Duration: p.stepDuration}, true
+}
+
+func (p *sineProfile) observe(step benchCfg.LoadStep, _ *StepResult) {
+	p.elapsed += step.Duration
+}
@@ -0,0 +1,114 @@
+package runner
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	benchCfg "csb/control/config"
+	"csb/control/constants"
+)
+
+// profileSteps returns up to n steps of a load profile
+func profileSteps(t *testing.T, profile loadProfile, n int) []benchCfg.LoadStep {
+	t.Helper()
+	var steps []benchCfg.LoadStep
+	for len(steps) < n {
+		step, ok := profile.next()
+		if !ok {
+			break
+		}
+		steps = append(steps, step)
+		profile.observe(step, nil)
+	}
+	return steps
+}
+
+func TestLoadProfileSteps(t *testing.T) {
+	s := time.Second
+	tests := []struct {
+		name   string
+		config func(cfg *BenchmarkRunConfig)
+		n      int
+		want   []benchCfg.LoadStep
+	}{
+		{
+			name: "ramp stops at the maximum",
+			config: func(cfg *BenchmarkRunConfig) {
+				cfg.LoadMode = constants.LOAD_MODE_RAMP
+				cfg.InitialClients, cfg.ClientStepSize, cfg.MaxClients = 2, 2, 5
+			},
+			n:    4,
+			want: []benchCfg.LoadStep{{Level: 2, Duration: s}, {Level: 4, Duration: s}, {Level: 5, Duration: s}, {Level: 5, Duration: s}},
+		},
+		{
+			name: "ramp up and down",
+			config: func(cfg *BenchmarkRunConfig) {
+				cfg.LoadMode = constants.LOAD_MODE_RAMP_UP_DOWN
+				cfg.InitialClients, cfg.ClientStepSize, cfg.MaxClients = 1, 2, 5
+			},
+			n: 7,
+			want: []benchCfg.LoadStep{
+				{Level: 1, Duration: s}, {Level: 3, Duration: s}, {Level: 5, Duration: s}, {Level: 3, Duration: s},
+				{Level: 1, Duration: s}, {Level: 3, Duration: s}, {Level: 5, Duration: s},
+			},
+		},
+		{
+			name: "steps end with the schedule",
+			config: func(cfg *BenchmarkRunConfig) {
+				cfg.LoadMode = constants.LOAD_MODE_STEPS
+				cfg.LoadSchedule = []string{"2@2s", "4", "1@3s"}
+			},
+			n:    10,
+			want: []benchCfg.LoadStep{{Level: 2, Duration: 2 * s}, {Level: 4, Duration: s}, {Level: 1, Duration: 3 * s}},
+		},
+		{
+			name: "spike alternates with the base level",
+			config: func(cfg *BenchmarkRunConfig) {
+				cfg.LoadMode = constants.LOAD_MODE_SPIKE
+				cfg.InitialClients, cfg.MaxClients = 2, 10
+				cfg.SpikeInterval, cfg.SpikeDuration = benchCfg.Duration(10*s), benchCfg.Duration(2*s)
+			},
+			n:    4,
+			want: []benchCfg.LoadStep{{Level: 2, Duration: 8 * s}, {Level: 10, Duration: 2 * s}, {Level: 2, Duration: 8 * s}, {Level: 10, Duration: 2 * s}},
+		},
+		{
+			name: "sine covers a full period",
+			config: func(cfg *BenchmarkRunConfig) {
+				cfg.LoadMode = constants.LOAD_MODE_SINE
+				cfg.InitialClients, cfg.MaxClients = 1, 9
+				cfg.SinePeriod = benchCfg.Duration(8 * s)
+			},
+			n: 9,
+			want: []benchCfg.LoadStep{
+				{Level: 1, Duration: s}, {Level: 2, Duration: s}, {Level: 5, Duration: s}, {Level: 8, Duration: s}, {Level: 9, Duration: s},
+				{Level: 8, Duration: s}, {Level: 5, Duration: s}, {Level: 2, Duration: s}, {Level: 1, Duration: s},
+			},
+		},
+		{
+			name: "open loop levels are rates",
+			config: func(cfg *BenchmarkRunConfig) {
+				cfg.LoadMode = constants.LOAD_MODE_RAMP
+				cfg.LoopMode = constants.LOOP_MODE_OPEN
+				cfg.InitialRate, cfg.RateStepSize, cfg.MaxRate = 100, 400, 1000
+			},
+			n:    4,
+			want: []benchCfg.LoadStep{{Level: 100, Duration: s}, {Level: 500, Duration: s}, {Level: 900, Duration: s}, {Level: 1000, Duration: s}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &BenchmarkRunConfig{BenchctlConfig: *benchCfg.GetDefaultConfig()}
+			cfg.StepDuration = benchCfg.Duration(s)
+			tt.config(cfg)
+			profile, err := newLoadProfile(cfg)
+			if err != nil {
+				t.Fatalf("newLoadProfile() error = %v", err)
+			}
+			if got := profileSteps(t, profile, tt.n); !slices.Equal(got, tt.want) {
+				t.Errorf("steps = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
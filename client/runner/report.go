@@ -0,0 +1,233 @@
+package runner
+
+import (
+	pb "csb/api/benchmarkpb"
+	"sort"
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// Range and precision of the latency histograms, the latencies are recorded
+// in microseconds
+const (
+	minTrackableLatencyUs     = 1
+	maxTrackableLatencyUs     = int64(time.Hour / time.Microsecond)
+	latencySignificantFigures = 3
+)
+
+func newStepResult(index int, numClients int, rate int, isWarmup bool) *StepResult {
+	phase := "main"
+	if isWarmup {
+		phase = "warmup"
+	}
+	return &StepResult{
+		Index:            index,
+		Phase:            phase,
+		NumClients:       numClients,
+		TargetRate:       rate,
+		StartTime:        time.Now(),
+		Latencies:        newLatencyHistogram(),
+		SuccessLatencies: newLatencyHistogram(),
+		ErrorCodes:       make(map[int]int64),
+		ByOperation:      make(map[string]*OperationResult),
+	}
+}
+
+func newLatencyHistogram() *hdrhistogram.Histogram {
+	return hdrhistogram.New(minTrackableLatencyUs, maxTrackableLatencyUs, latencySignificantFigures)
+}
+
+// operationSample is a finished operation of a step
+type operationSample struct {
+	label   string // operation label, see operationLabel
+	latency time.Duration
+	bytes   int64 // bytes written and read by the operation
+	err     error
+}
+
+// newOperationSample returns the sample of a finished operation
+func newOperationSample(metric Metric) operationSample {
+	req := metric.Request()
+	sample := operationSample{label: operationLabel(metric), latency: req.Latency, err: req.Err}
+	if kv, ok := metric.(*KVMetric); ok {
+		sample.bytes = kv.RequestBytes + kv.ResponseBytes
+	}
+	return sample
+}
+
+// operationLabel returns the label the results of an operation are grouped
+// by, the operation name followed by the read consistency of reads and the
+// size class of large objects
+func operationLabel(metric Metric) string {
+	req := metric.Request()
+	label := req.Operation
+	if kv, ok := metric.(*KVMetric); ok {
+		if kv.Consistency != "" {
+			label += "/" + kv.Consistency
+		}
+		if kv.SizeClass != "" {
+			label += "/" + kv.SizeClass
+		}
+	}
+	return label
+}
+
+// addOperation counts a finished operation and records its latency, failed
+// operations are also counted by their status code and only the bytes of
+// successful operations add up to the bandwidth
+func (res *StepResult) addOperation(sample operationSample) {
+	res.mu.Lock()
+	defer res.mu.Unlock()
+	op, ok := res.ByOperation[sample.label]
+	if !ok {
+		op = &OperationResult{Latencies: newLatencyHistogram()}
+		res.ByOperation[sample.label] = op
+	}
+	res.Operations++
+	op.Operations++
+	if sample.err != nil {
+		res.Errors++
+		op.Errors++
+		statusCode, _ := GetErrInfo(sample.err)
+		res.ErrorCodes[statusCode]++
+	} else {
+		res.Bytes += sample.bytes
+		op.Bytes += sample.bytes
+	}
+	recordLatency(res.Latencies, sample.latency)
+	recordLatency(op.Latencies, sample.latency)
+	if sample.err == nil {
+		recordLatency(res.SuccessLatencies, sample.latency)
+	}
+}
+
+// recordLatency adds the latency of an operation to a histogram, latencies
+// outside of the trackable range are clamped to it
+func recordLatency(hist *hdrhistogram.Histogram, latency time.Duration) {
+	us := min(max(latency.Microseconds(), minTrackableLatencyUs), maxTrackableLatencyUs)
+	hist.RecordValue(us)
+}
+
+// calculateLatencies computes the latency statistics of the step
+func (res *StepResult) calculateLatencies() {
+	if res.Latencies.TotalCount() == 0 {
+		return
+	}
+
+	res.P50Latency = res.Percentile(0.5)
+	res.P90Latency = res.Percentile(0.9)
+	res.P99Latency = res.Percentile(0.99)
+	res.P999Latency = res.Percentile(0.999)
+	res.MaxLatency = time.Duration(res.Latencies.Max()) * time.Microsecond
+	res.MeanLatency = time.Duration(res.Latencies.Mean() * float64(time.Microsecond))
+	res.StdDev = time.Duration(res.Latencies.StdDev() * float64(time.Microsecond))
+}
+
+// Percentile returns the latency below which the fraction p of all
+// operations of the step fall
+func (res *StepResult) Percentile(p float64) time.Duration {
+	if res.Latencies.TotalCount() == 0 {
+		return 0
+	}
+	return time.Duration(res.Latencies.ValueAtQuantile(p*100)) * time.Microsecond
+}
+
+// SuccessPercentile returns the latency below which the fraction p of the
+// successful operations of the step fall
+func (res *StepResult) SuccessPercentile(p float64) time.Duration {
+	if res.SuccessLatencies.TotalCount() == 0 {
+		return 0
+	}
+	return time.Duration(res.SuccessLatencies.ValueAtQuantile(p*100)) * time.Microsecond
+}
+
+// ErrorRate returns the share of the operations of the step which failed
+func (res *StepResult) ErrorRate() float64 {
+	if res.Operations == 0 {
+		return 0
+	}
+	return float64(res.Errors) / float64(res.Operations)
+}
+
+// Throughput returns the number of operations per second
+func (res *StepResult) Throughput() float64 {
+	elapsed := res.EndTime.Sub(res.StartTime).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(res.Operations) / elapsed
+}
+
+// Bandwidth returns the number of megabytes (10^6 bytes) written and read
+// per second
+func (res *StepResult) Bandwidth() float64 {
+	elapsed := res.EndTime.Sub(res.StartTime).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(res.Bytes) / 1e6 / elapsed
+}
+
+func (res *StepResult) ToStepReport() *pb.StepReport {
+	errorCounts := make(map[int32]int64, len(res.ErrorCodes))
+	for code, count := range res.ErrorCodes {
+		errorCounts[int32(code)] = count
+	}
+	// the labels are sorted, so that the reports of all steps list the
+	// operations in the same order
+	labels := make([]string, 0, len(res.ByOperation))
+	for label := range res.ByOperation {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	operationReports := make([]*pb.OperationReport, 0, len(labels))
+	for _, label := range labels {
+		operationReports = append(operationReports, res.ByOperation[label].toOperationReport(label, res.EndTime.Sub(res.StartTime)))
+	}
+
+	return &pb.StepReport{
+		StepIndex:        int32(res.Index),
+		Phase:            res.Phase,
+		NumClients:       int32(res.NumClients),
+		StartUnixNano:    res.StartTime.UnixNano(),
+		EndUnixNano:      res.EndTime.UnixNano(),
+		Operations:       res.Operations,
+		Errors:           res.Errors,
+		Throughput:       res.Throughput(),
+		LatencyP50Us:     res.P50Latency.Microseconds(),
+		LatencyP90Us:     res.P90Latency.Microseconds(),
+		LatencyP99Us:     res.P99Latency.Microseconds(),
+		LatencyP999Us:    res.P999Latency.Microseconds(),
+		LatencyMaxUs:     res.MaxLatency.Microseconds(),
+		LatencyMeanUs:    res.MeanLatency.Microseconds(),
+		LatencyStddevUs:  res.StdDev.Microseconds(),
+		ErrorCounts:      errorCounts,
+		TargetRate:       int64(res.TargetRate),
+		OperationReports: operationReports,
+		Bytes:            res.Bytes,
+		Bandwidth:        res.Bandwidth(),
+		EventsPerWatcher: res.EventsPerWorker,
+	}
+}
+
+func (op *OperationResult) toOperationReport(label string, elapsed time.Duration) *pb.OperationReport {
+	report := &pb.OperationReport{
+		Operation:  label,
+		Operations: op.Operations,
+		Errors:     op.Errors,
+		Bytes:      op.Bytes,
+	}
+	if elapsed > 0 {
+		report.Throughput = float64(op.Operations) / elapsed.Seconds()
+		report.Bandwidth = float64(op.Bytes) / 1e6 / elapsed.Seconds()
+	}
+	if op.Latencies.TotalCount() > 0 {
+		report.LatencyMeanUs = int64(op.Latencies.Mean())
+		report.LatencyP50Us = op.Latencies.ValueAtQuantile(50)
+		report.LatencyP90Us = op.Latencies.ValueAtQuantile(90)
+		report.LatencyP99Us = op.Latencies.ValueAtQuantile(99)
+		report.LatencyMaxUs = op.Latencies.Max()
+	}
+	return report
+}
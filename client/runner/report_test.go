@@ -0,0 +1,22 @@
+package runner
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAddOperationCountsBytesOfSuccessfulOperations(t *testing.T) {
+	result := newStepResult(1, 1, 0, false)
+	result.addOperation(operationSample{label: "put", latency: time.Millisecond, bytes: 100})
+	result.addOperation(operationSample{label: "put", latency: time.Millisecond, bytes: 50, err: errors.New("failed")})
+
+	// Test that the bytes of the failed operation do not add up to the
+	// bandwidth
+	if result.Bytes != 100 {
+		t.Errorf("Bytes = %d, want 100", result.Bytes)
+	}
+	if op := result.ByOperation["put"]; op.Bytes != 100 || op.Operations != 2 || op.Errors != 1 {
+		t.Errorf("put result = %d bytes, %d operations, %d errors, want 100, 2, 1", op.Bytes, op.Operations, op.Errors)
+	}
+}
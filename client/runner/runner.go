@@ -0,0 +1,345 @@
+package runner
+
+import (
+	"context"
+	grpcserver "csb/client/grpc"
+	lg "csb/client/logger"
+	"csb/control/constants"
+	generator "csb/data-generator"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"go.uber.org/zap"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// NewBenchmarkRunner creates the runner of the configured scenario and
+// workload type, the workload is looked up in the registry
+func NewBenchmarkRunner(config *BenchmarkRunConfig, logger *lg.Logger) (*BenchmarkRunner, error) {
+	rg := rand.New(rand.NewSource(config.Seed + config.SeedOffset))
+	r := &BenchmarkRunner{
+		config:      config,
+		clients:     make([]*clientv3.Client, 0, config.InitialClients),
+		results:     make([]*StepResult, 0),
+		rand:        rg,
+		generator:   generator.NewGenerator(rg),
+		logger:      logger,
+		keyChoosers: make(map[int]KeyChooser),
+	}
+
+	var err error
+	r.newKeyChooser, err = newKeyChooserFactory(config, len(config.Keys))
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := NewValueGenerator(&config.BenchctlConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create value generator: %w", err)
+	}
+
+	workload, err := NewWorkload(&WorkloadEnv{
+		Config:    config,
+		Generator: r.generator,
+		Values:    values,
+		Logger:    logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.workload = workload
+
+	if err := workload.Setup(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to set up workload: %w", err)
+	}
+	if err := r.addClients(config.InitialClients); err != nil {
+		r.Close()
+		return nil, err
+	}
+
+	r.metricsExporter, err = NewMetricsExporter(config.MetricsFile, config.MetricsBatchSize, workload.MetricHeader())
+	if err != nil {
+		r.Close()
+		return nil, fmt.Errorf("failed to create metrics exporter: %w", err)
+	}
+	return r, nil
+}
+
+func (r *BenchmarkRunner) Close() error {
+	var lastErr error
+	if err := r.workload.Teardown(); err != nil {
+		lastErr = fmt.Errorf("failed to tear down workload: %w", err)
+	}
+	for i, cli := range r.clients {
+		if err := cli.Close(); err != nil {
+			lastErr = fmt.Errorf("failed to close client %d: %w", i, err)
+		}
+	}
+	return lastErr
+}
+
+func (r *BenchmarkRunner) GetResults() []*StepResult {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	return r.results
+}
+
+func (r *BenchmarkRunner) addClients(numNewClients int) error {
+	preparer, _ := r.workload.(ClientPreparer)
+	for i := 0; i < numNewClients; i++ {
+		cli, err := clientv3.New(clientv3.Config{
+			Endpoints:   r.config.Endpoints,
+			DialTimeout: 5 * time.Second,
+			Logger:      zap.NewNop(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create etcd client %d: %w", len(r.clients), err)
+		}
+		if preparer != nil {
+			if err := preparer.PrepareClient(cli); err != nil {
+				cli.Close()
+				return fmt.Errorf("failed to prepare etcd client %d: %w", len(r.clients), err)
+			}
+		}
+		r.clients = append(r.clients, cli)
+	}
+	return nil
+}
+
+// ensureClients grows the pool of etcd clients to at least numClients
+func (r *BenchmarkRunner) ensureClients(numClients int) error {
+	if numClients <= len(r.clients) {
+		return nil
+	}
+	return r.addClients(numClients - len(r.clients))
+}
+
+// prepareStep sets up the etcd clients and the workload for a step with the
+// given number of workers
+func (r *BenchmarkRunner) prepareStep(numWorkers int) error {
+	if err := r.ensureClients(numWorkers); err != nil {
+		return err
+	}
+	if preparer, ok := r.workload.(StepPreparer); ok {
+		if err := preparer.PrepareStep(numWorkers); err != nil {
+			return fmt.Errorf("failed to prepare step: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *BenchmarkRunner) runLoadStep(ctx context.Context, index int, numClients int, rate int, isWarmup bool) (*StepResult, error) {
+	result := newStepResult(index, numClients, rate, isWarmup)
+	runPhase := result.Phase
+
+	sampleChan := make(chan operationSample, numClients*int(time.Duration(r.config.StepDuration).Seconds()))
+	collectorDone := make(chan struct{})
+
+	// Start a separate goroutine to collect the operations
+	go func() {
+		defer close(collectorDone)
+		for sample := range sampleChan {
+			result.addOperation(sample)
+		}
+	}()
+
+	// the key choosers of the workers keep their state across steps
+	for clientID := len(r.keyChoosers); clientID < numClients; clientID++ {
+		r.keyChoosers[clientID] = r.newKeyChooser(r.config.ClientIDOffset + clientID)
+	}
+
+	// record counts a finished operation of a worker and exports its metric
+	record := func(clientID int, metric Metric) {
+		req := metric.Request()
+		// operations cut off by the end of the step are not counted
+		if req.Err != nil && ctx.Err() != nil {
+			return
+		}
+		sampleChan <- newOperationSample(metric)
+
+		go func() {
+			// Record raw metric
+			if req.Timestamp.IsZero() {
+				req.Timestamp = time.Now()
+			}
+			req.NumClients = numClients
+			req.ClientID = clientID
+			req.RunPhase = runPhase
+
+			// Add metric to exporter
+			if r.metricsExporter != nil {
+				if err := r.metricsExporter.AddMetric(metric); err != nil {
+					r.logger.Printf("Failed to export metric: %v", err)
+				}
+			}
+		}()
+	}
+
+	// operations outside of the workers are recorded until the end of the step
+	recorder, _ := r.workload.(BackgroundRecorder)
+	if recorder != nil {
+		recorder.RecordTo(func(metric Metric) {
+			record(-1, metric)
+		})
+	}
+
+	// the events received before the step are not part of it
+	counter, _ := r.workload.(EventCounter)
+	if counter != nil {
+		counter.TakeEvents()
+	}
+
+	// Start client goroutines
+	runWorkers(ctx, numClients, rate, func(clientID int) requestFunc {
+		w := &Worker{
+			ID: clientID,
+			// per goroutine random generator
+			Rand: r.generator.NewRand(r.config.Seed+r.config.SeedOffset, clientID),
+			Keys: r.keyChoosers[clientID],
+			// Get the assigned client from the pool
+			Client:     r.clients[clientID%len(r.clients)],
+			NumClients: numClients,
+			RunPhase:   runPhase,
+		}
+
+		// the think time is drawn from the random generator of the worker
+		pace := newPacer(r.config, w.Rand)
+
+		return func(start time.Time) {
+			w.Start = start
+			record(r.config.ClientIDOffset+clientID, r.workload.Execute(ctx, w))
+			if pace != nil {
+				pace.wait(ctx, start)
+			}
+		}
+	})
+
+	if recorder != nil {
+		recorder.RecordTo(nil)
+	}
+	close(sampleChan)
+	<-collectorDone
+	result.EndTime = time.Now()
+	if counter != nil {
+		result.EventsPerWorker = float64(counter.TakeEvents()) / result.EndTime.Sub(result.StartTime).Seconds() / float64(numClients)
+	}
+
+	// Calculate latency percentiles
+	result.calculateLatencies()
+
+	return result, nil
+}
+
+func (r *BenchmarkRunner) Run(s *grpcserver.BenchmarkServiceServer) error {
+	// the size of the key space and the database is sampled during all steps
+	statsCtx, statsCancel := context.WithCancel(context.Background())
+	statsDone := make(chan struct{})
+	go func() {
+		defer close(statsDone)
+		if err := r.sampleDBStats(statsCtx, constants.DEFAULT_DB_STATS_FILE); err != nil {
+			r.logger.Printf("Failed to sample the database size: %v", err)
+		}
+	}()
+	err := r.runSteps(s)
+	statsCancel()
+	<-statsDone
+	if err != nil {
+		return err
+	}
+
+	if r.metricsExporter != nil {
+		if err := r.metricsExporter.Close(); err != nil {
+			s.SendBenchmarkStatus("Failed to close metrics exporter")
+			r.logger.Printf("Failed to close metrics exporter: %v", err)
+		}
+	}
+
+	s.SendBenchmarkStatus("All benchmark steps are completed")
+	r.logger.Printf("All benchmark steps are completed")
+	return nil
+}
+
+// runSteps runs the warm-up and the main steps of the load profile, the
+// result of every step is reported to the control program
+func (r *BenchmarkRunner) runSteps(s *grpcserver.BenchmarkServiceServer) error {
+	profile, err := newLoadProfile(r.config)
+	if err != nil {
+		s.SendBenchmarkStatus("Invalid load profile")
+		return err
+	}
+	// the warm-up runs at the load of the first step
+	firstStep, ok := profile.next()
+	if !ok {
+		return fmt.Errorf("load profile %s has no steps", r.config.LoadMode)
+	}
+
+	// Warm-up period
+	reportStr := fmt.Sprintf("Starting warm-up step (%v)...", r.config.WarmupDuration)
+	r.logger.Println(reportStr)
+	s.SendBenchmarkStatus(reportStr)
+	// Steps are scheduled at absolute instants derived from the start time,
+	// so that they stay aligned with the other benchmark clients
+	stepStart := r.config.StartTime
+	warmupClients, warmupRate := stepLoad(r.config, firstStep.Level)
+	if err := r.prepareStep(warmupClients); err != nil {
+		return err
+	}
+	time.Sleep(time.Until(stepStart))
+	warmupCtx, warmupCancel := context.WithDeadline(context.Background(), stepStart.Add(time.Duration(r.config.WarmupDuration)))
+	defer warmupCancel()
+	warmupResult, err := r.runLoadStep(warmupCtx, 0, warmupClients, warmupRate, true)
+	if err != nil {
+		s.SendBenchmarkStatus("Warm-up failed")
+		return fmt.Errorf("warm-up failed: %w", err)
+	}
+	reportStr = fmt.Sprintf("Warm-up step completed with %s (P99: %dms), #Ops: %d, #Errors: %d", describeLoad(warmupClients, warmupRate), warmupResult.P99Latency.Milliseconds(), warmupResult.Operations, warmupResult.Errors)
+	r.logger.Println(reportStr)
+	s.SendStepReport(warmupResult.ToStepReport())
+
+	// Main benchmark loop, the load profile picks the load of every step
+	stepStart = stepStart.Add(time.Duration(r.config.WarmupDuration))
+	remainingTime := time.Duration(r.config.TotalDuration)
+
+	for stepIndex := 1; remainingTime > 0; stepIndex++ {
+		step, ok := profile.next()
+		if !ok {
+			break
+		}
+		curNumClients, rate := stepLoad(r.config, step.Level)
+		if err := r.prepareStep(curNumClients); err != nil {
+			return err
+		}
+		reportStr = fmt.Sprintf("Starting step with %s...", describeLoad(curNumClients, rate))
+		r.logger.Println(reportStr)
+		s.SendBenchmarkStatus(reportStr)
+		actualDuration := min(step.Duration, remainingTime)
+		time.Sleep(time.Until(stepStart))
+		stepCtx, stepCancel := context.WithDeadline(context.Background(), stepStart.Add(actualDuration))
+		stepStart = stepStart.Add(actualDuration)
+		result, err := r.runLoadStep(stepCtx, stepIndex, curNumClients, rate, false)
+		stepCancel()
+
+		if err != nil {
+			s.SendBenchmarkStatus("Step failed")
+			return fmt.Errorf("step failed with %s: %w", describeLoad(curNumClients, rate), err)
+		}
+
+		r.mut.Lock()
+		r.results = append(r.results, result)
+		r.mut.Unlock()
+
+		reportStr = fmt.Sprintf("Step completed with %s (P99: %dms), #Ops: %d, #Errors: %d", describeLoad(curNumClients, rate), result.P99Latency.Milliseconds(), result.Operations, result.Errors)
+		r.logger.Println(reportStr)
+		s.SendStepReport(result.ToStepReport())
+
+		profile.observe(step, result)
+		remainingTime -= actualDuration
+	}
+
+	if search, ok := profile.(*capacitySearch); ok {
+		reportCapacity(s, r.logger, search)
+	}
+	return nil
+}
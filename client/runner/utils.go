@@ -2,8 +2,11 @@ package runner
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"time"
 
+	"google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
 
 	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
@@ -25,6 +28,24 @@ func GetErrInfo(err error) (int, string) {
 		// ctx is attached with a deadline and it exceeded
 		statusCode = -2
 		statusText = "Request deadline exceeded"
+	} else if errors.Is(err, errTxnConflict) || errors.Is(err, errObjectExists) || errors.Is(err, errObjectNotFound) {
+		// the compare of the transaction failed, because of conflicting
+		// transactions or an object which exists or is missing
+		statusCode = -5
+		statusText = err.Error()
+	} else if errors.Is(err, rpctypes.ErrCompacted) {
+		// the revision read is older than the last compaction
+		statusCode = -6
+		statusText = err.Error()
+	} else if errors.Is(err, rpctypes.ErrRequestTooLarge) {
+		// the request exceeds the max request size of the etcd server
+		statusCode = -7
+		statusText = err.Error()
+	} else if isMessageSizeErr(err) {
+		// the gRPC message exceeds the max message size of the client or
+		// the server
+		statusCode = -8
+		statusText = err.Error()
 	} else if statusErr, ok := err.(rpctypes.EtcdError); ok {
 		// etcd client rpc error
 		statusCode = int(statusErr.Code())
@@ -43,3 +64,10 @@ func GetErrInfo(err error) (int, string) {
 	}
 	return statusCode, statusText
 }
+
+// isMessageSizeErr reports whether the error is a gRPC message which is
+// larger than allowed by the client or the server
+func isMessageSizeErr(err error) bool {
+	ev, ok := status.FromError(err)
+	return ok && ev.Code() == codes.ResourceExhausted && strings.Contains(ev.Message(), "message larger than max")
+}
@@ -0,0 +1,103 @@
+package runner
+
+import (
+	"context"
+	lg "csb/client/logger"
+	generator "csb/data-generator"
+	"fmt"
+	"math/rand"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// Workload defines the operations of a benchmark scenario, the step engine
+// takes care of the load steps, the clients and the metrics
+type Workload interface {
+	// Setup prepares the workload before the warm-up
+	Setup(ctx context.Context) error
+	// Execute runs a single operation of the worker and returns its metric,
+	// the latency of the operation is measured from the start of the worker
+	Execute(ctx context.Context, w *Worker) Metric
+	// Teardown releases the resources of the workload after the last step
+	Teardown() error
+	// MetricHeader returns the CSV header of the metrics of the workload
+	MetricHeader() []string
+}
+
+// ClientPreparer is implemented by workloads which keep state per etcd
+// client, e.g. a session, it is called for every client added to the pool
+type ClientPreparer interface {
+	PrepareClient(cli *clientv3.Client) error
+}
+
+// StepPreparer is implemented by workloads which keep state per worker,
+// e.g. a watch, it is called with the number of workers before every step
+type StepPreparer interface {
+	PrepareStep(numWorkers int) error
+}
+
+// BackgroundRecorder is implemented by workloads with operations outside of
+// the workers, e.g. watches, which are counted in the results of the
+// current step. The step engine passes the record function at the start of
+// every step and nil at its end, the workload must not call the function
+// after it was replaced. The operations are recorded with the client ID -1.
+type BackgroundRecorder interface {
+	RecordTo(record func(Metric))
+}
+
+// EventCounter is implemented by workloads whose workers receive events,
+// e.g. watches, TakeEvents returns the number of events received since its
+// last call. The step engine reports the events per second of every worker.
+type EventCounter interface {
+	TakeEvents() int64
+}
+
+// Worker is a single benchmark client within a load step
+type Worker struct {
+	ID         int // client ID within this benchmark client
+	Client     *clientv3.Client
+	Rand       *rand.Rand
+	Keys       KeyChooser // picks the indices of the keys of the operations
+	NumClients int        // number of clients of the step
+	RunPhase   string     // run phase of the step
+	// Start is the instant the latency of the current operation is measured
+	// from, the intended send time in open-loop mode
+	Start time.Time
+}
+
+// WorkloadEnv holds what a workload shares with the step engine
+type WorkloadEnv struct {
+	Config    *BenchmarkRunConfig
+	Generator *generator.Generator
+	Values    *generator.ValueGenerator // generates the values of the writes
+	Logger    *lg.Logger
+}
+
+// WorkloadFactory creates a workload for a benchmark run
+type WorkloadFactory func(env *WorkloadEnv) (Workload, error)
+
+// registry of the workloads by scenario and workload type
+var registry = make(map[string]map[string]WorkloadFactory)
+
+// Register makes a workload available for the given scenario and workload
+// type, it panics if the workload type is registered twice
+func Register(scenario string, workloadType string, factory WorkloadFactory) {
+	if registry[scenario] == nil {
+		registry[scenario] = make(map[string]WorkloadFactory)
+	}
+	if _, ok := registry[scenario][workloadType]; ok {
+		panic(fmt.Sprintf("workload %s/%s is already registered", scenario, workloadType))
+	}
+	registry[scenario][workloadType] = factory
+}
+
+// NewWorkload creates the registered workload of the configured scenario
+// and workload type
+func NewWorkload(env *WorkloadEnv) (Workload, error) {
+	factory, ok := registry[env.Config.Scenario][env.Config.WorkloadType]
+	if !ok {
+		return nil, fmt.Errorf("unknown workload %s for scenario %s", env.Config.WorkloadType, env.Config.Scenario)
+	}
+	return factory(env)
+}
@@ -0,0 +1,639 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	lg "csb/client/logger"
+	"csb/control/constants"
+	generator "csb/data-generator"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+)
+
+func init() {
+	Register(constants.SCENARIO_KUBERNETES, constants.WORKLOAD_TYPE_APISERVER, NewAPIServerWorkload)
+}
+
+var (
+	// errObjectNotFound is returned when an object to update or delete no
+	// longer exists
+	errObjectNotFound = errors.New("object not found")
+	// errObjectExists is returned when an object to create already exists
+	errObjectExists = errors.New("object already exists")
+)
+
+// Requests of the kube-apiserver to etcd with their weights, the reads which
+// the apiserver serves from its watch cache are left out
+var apiServerOperations = []struct {
+	name   string
+	weight int
+}{
+	{"get", 30},    // read an object
+	{"list", 5},    // paginated list of a resource type, in a namespace or cluster-wide
+	{"update", 35}, // read-modify-write guarded by the mod revision of the object
+	{"create", 5},  // create an object if its key does not exist
+	{"delete", 5},  // delete an object guarded by its mod revision
+	{"event", 20},  // write an event attached to a lease
+}
+
+const (
+	// Resource type of the events, their keys are attached to leases
+	eventResource = "events"
+	// Key holding the revision of the last compaction, shared by all
+	// compactors like in the kube-apiserver
+	compactRevKey = "compact_rev_key"
+	// The lease of the events is reused for up to a minute, like with the
+	// --lease-reuse-duration-seconds of the kube-apiserver
+	eventLeaseReuse = time.Minute
+)
+
+// watchEvent is an event received by a watch
+type watchEvent struct {
+	key  string
+	size int64
+	rev  int64
+	recv time.Time
+}
+
+// writeRecord is a revision whose watch events are matched with the write.
+// A watch may receive the event before the response of the write arrives,
+// such events are kept until the write returns.
+type writeRecord struct {
+	start   time.Time    // start of the write request, zero until it returns
+	pending int          // watches which did not yet receive the event
+	early   []watchEvent // events received before the write returned
+	added   time.Time    // the record is dropped after the max wait time
+}
+
+// APIServerWorkload models the access pattern of the kube-apiserver on the
+// objects of a cluster: gets, paginated lists, updates and deletes guarded
+// by the mod revision, creates guarded by the create revision, events
+// attached to shared leases, a long-running watch per resource type and a
+// periodic compaction. The watch events and the compactions are recorded as
+// operations of the current step.
+type APIServerWorkload struct {
+	config    *BenchmarkRunConfig
+	generator *generator.Generator
+	values    *generator.ValueGenerator
+	logger    *lg.Logger
+
+	keys        *KeySpace    // keys of the objects, without the events
+	created     atomic.Int64 // sequence of the names of created objects
+	resources   []string     // watched resource types
+	totalWeight int
+
+	// background watches and compaction
+	bgClient *clientv3.Client
+	stopBg   context.CancelFunc
+	bgDone   sync.WaitGroup
+
+	// writes by revision, until their events were received by all watches
+	// or the max wait time passed
+	writes  map[int64]*writeRecord
+	writeMu sync.Mutex
+
+	// lease of the events and its grant time
+	eventLease   clientv3.LeaseID
+	eventLeaseAt time.Time
+	leaseMu      sync.Mutex
+
+	// record function of the current step
+	record   func(Metric)
+	recordMu sync.Mutex
+}
+
+func NewAPIServerWorkload(env *WorkloadEnv) (Workload, error) {
+	aw := &APIServerWorkload{
+		config:    env.Config,
+		generator: env.Generator,
+		values:    env.Values,
+		logger:    env.Logger,
+		writes:    make(map[int64]*writeRecord),
+	}
+	for _, op := range apiServerOperations {
+		aw.totalWeight += op.weight
+	}
+	return aw, nil
+}
+
+func (aw *APIServerWorkload) Setup(ctx context.Context) error {
+	if len(aw.config.Keys) == 0 {
+		return errors.New("no objects to operate on")
+	}
+	aw.keys = NewKeySpace(aw.config.Keys, aw.config.KeySize)
+	objects, err := GetObjectCounts(&aw.config.BenchctlConfig)
+	if err != nil {
+		return err
+	}
+	for _, o := range objects {
+		aw.resources = append(aw.resources, o.Resource)
+	}
+	aw.resources = append(aw.resources, eventResource)
+
+	aw.bgClient, err = clientv3.New(clientv3.Config{
+		Endpoints:   aw.config.Endpoints,
+		DialTimeout: 5 * time.Second,
+		Logger:      zap.NewNop(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create etcd client for the watches: %w", err)
+	}
+
+	// the watches and the compaction run until the teardown, independent
+	// of the steps
+	bgCtx, cancel := context.WithCancel(context.Background())
+	aw.stopBg = cancel
+	for _, resource := range aw.resources {
+		for i := 0; i < aw.config.K8sWatchesPerResource; i++ {
+			aw.bgDone.Add(1)
+			go func() {
+				defer aw.bgDone.Done()
+				aw.watch(bgCtx, generator.ObjectKeyPrefix+resource+"/")
+			}()
+		}
+	}
+	if aw.config.K8sWatchesPerResource > 0 {
+		aw.bgDone.Add(1)
+		go func() {
+			defer aw.bgDone.Done()
+			aw.expireWrites(bgCtx)
+		}()
+	}
+	if aw.config.K8sCompactionInterval > 0 {
+		aw.bgDone.Add(1)
+		go func() {
+			defer aw.bgDone.Done()
+			aw.compact(bgCtx)
+		}()
+	}
+	return nil
+}
+
+func (aw *APIServerWorkload) Teardown() error {
+	if aw.stopBg == nil {
+		return nil
+	}
+	aw.stopBg()
+	aw.bgDone.Wait()
+	return aw.bgClient.Close()
+}
+
+// KeySpace returns the keys of the objects, which change with creates and
+// deletes
+func (aw *APIServerWorkload) KeySpace() *KeySpace {
+	return aw.keys
+}
+
+// RecordTo sets the function the watch events and the compactions are
+// recorded with
+func (aw *APIServerWorkload) RecordTo(record func(Metric)) {
+	aw.recordMu.Lock()
+	defer aw.recordMu.Unlock()
+	aw.record = record
+}
+
+// recordMetric records an operation outside of the workers, it is dropped
+// between the steps
+func (aw *APIServerWorkload) recordMetric(metric Metric) {
+	aw.recordMu.Lock()
+	defer aw.recordMu.Unlock()
+	if aw.record != nil {
+		aw.record(metric)
+	}
+}
+
+func (aw *APIServerWorkload) MetricHeader() []string {
+	return (&KVMetric{RequestMetric: &RequestMetric{}}).ToCSVHeader()
+}
+
+// nextOperation picks a request of the kube-apiserver according to the weights
+func (aw *APIServerWorkload) nextOperation(w *Worker) string {
+	n := w.Rand.Intn(aw.totalWeight)
+	for _, op := range apiServerOperations {
+		if n < op.weight {
+			return op.name
+		}
+		n -= op.weight
+	}
+	return apiServerOperations[len(apiServerOperations)-1].name
+}
+
+func (aw *APIServerWorkload) Execute(ctx context.Context, w *Worker) Metric {
+	operation := aw.nextOperation(w)
+	index := w.Keys.Next(w.Rand, aw.keys.Len())
+	key := aw.keys.Key(index)
+	timeoutCtx, cancel := context.WithTimeout(ctx, time.Duration(aw.config.MaxWaitTime))
+	defer cancel()
+
+	metric := &KVMetric{RequestMetric: &RequestMetric{Operation: operation}}
+	var err error
+	switch operation {
+	case "get":
+		var resp *clientv3.GetResponse
+		resp, err = w.Client.Get(timeoutCtx, key)
+		if err == nil {
+			metric.KeysScanned = int64(len(resp.Kvs))
+			metric.ResponseBytes = int64((*etcdserverpb.RangeResponse)(resp).Size())
+		}
+	case "list":
+		// namespaced resources are listed in the namespace of the object
+		// or across all namespaces
+		resource, _ := generator.ObjectResource(key)
+		key, err = generator.ObjectPrefix(key, !generator.IsClusterScoped(resource) && w.Rand.Intn(2) == 0)
+		if err == nil {
+			opts := []clientv3.OpOption{clientv3.WithRange(clientv3.GetPrefixRangeEnd(key))}
+			metric.KeysScanned, metric.ResponseBytes, err = pagedRange(timeoutCtx, w.Client, key, aw.config.K8sListLimit, opts)
+		}
+	case "update":
+		err = aw.guardedUpdate(timeoutCtx, w, key, metric)
+	case "create":
+		key, err = aw.create(timeoutCtx, w, key, metric)
+	case "delete":
+		// no other worker picks the object while it is deleted, it leaves
+		// the key space once it is gone
+		key, err = aw.keys.Delete(index)
+		if err == nil {
+			err = aw.guardedDelete(timeoutCtx, w, key, metric)
+			aw.keys.FinishDelete(key, err == nil || errors.Is(err, errObjectNotFound))
+		}
+	case "event":
+		key, err = aw.event(timeoutCtx, w, key, metric)
+	}
+	metric.Timestamp = time.Now()
+	metric.Latency = time.Since(w.Start)
+	metric.Key = key
+	metric.Success = err == nil
+	metric.Err = err
+	if err != nil {
+		metric.StatusCode, metric.StatusText = GetErrInfo(err)
+	}
+	return metric
+}
+
+// guardedUpdate writes a new version of an object like the GuaranteedUpdate
+// of the kube-apiserver: the update only applies if the mod revision of the
+// object did not change since it was read, otherwise the current object
+// returned by the transaction is updated again
+func (aw *APIServerWorkload) guardedUpdate(ctx context.Context, w *Worker, key string, metric *KVMetric) error {
+	getResp, err := w.Client.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	if len(getResp.Kvs) == 0 {
+		return errObjectNotFound
+	}
+	rev := getResp.Kvs[0].ModRevision
+	value, _ := aw.values.Generate(w.Rand)
+	metric.TxnKeys = 1
+	for {
+		start := time.Now()
+		resp, err := w.Client.Txn(ctx).
+			If(clientv3.Compare(clientv3.ModRevision(key), "=", rev)).
+			Then(clientv3.OpPut(key, string(value))).
+			Else(clientv3.OpGet(key)).
+			Commit()
+		if err != nil {
+			return err
+		}
+		if resp.Succeeded {
+			aw.awaitEvent(resp.Header.Revision, start)
+			metric.RequestBytes = int64(len(key) + len(value))
+			return nil
+		}
+		metric.Conflicts++
+		kvs := resp.Responses[0].GetResponseRange().GetKvs()
+		if len(kvs) == 0 {
+			return errObjectNotFound
+		}
+		if metric.Retries == aw.config.TxnMaxRetries {
+			return errTxnConflict
+		}
+		metric.Retries++
+		rev = kvs[0].ModRevision
+	}
+}
+
+// guardedDelete deletes an object only if its mod revision did not change
+// since it was read, otherwise the deletion is retried with the current
+// mod revision
+func (aw *APIServerWorkload) guardedDelete(ctx context.Context, w *Worker, key string, metric *KVMetric) error {
+	getResp, err := w.Client.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	if len(getResp.Kvs) == 0 {
+		return errObjectNotFound
+	}
+	rev := getResp.Kvs[0].ModRevision
+	metric.TxnKeys = 1
+	for {
+		start := time.Now()
+		resp, err := w.Client.Txn(ctx).
+			If(clientv3.Compare(clientv3.ModRevision(key), "=", rev)).
+			Then(clientv3.OpDelete(key)).
+			Else(clientv3.OpGet(key)).
+			Commit()
+		if err != nil {
+			return err
+		}
+		if resp.Succeeded {
+			aw.awaitEvent(resp.Header.Revision, start)
+			metric.KeysScanned = resp.Responses[0].GetResponseDeleteRange().GetDeleted()
+			return nil
+		}
+		metric.Conflicts++
+		kvs := resp.Responses[0].GetResponseRange().GetKvs()
+		if len(kvs) == 0 {
+			return errObjectNotFound
+		}
+		if metric.Retries == aw.config.TxnMaxRetries {
+			return errTxnConflict
+		}
+		metric.Retries++
+		rev = kvs[0].ModRevision
+	}
+}
+
+// create writes a new object next to the given one, the object only is
+// created if its key does not exist yet, afterwards it joins the key space.
+// The names are unique per benchmark client, the random generators of the
+// workers start over in every step.
+func (aw *APIServerWorkload) create(ctx context.Context, w *Worker, key string, metric *KVMetric) (string, error) {
+	resource, err := generator.ObjectResource(key)
+	if err != nil {
+		return key, err
+	}
+	prefix, err := generator.ObjectPrefix(key, true)
+	if err != nil {
+		return key, err
+	}
+	key = prefix + generator.ObjectName(resource, fmt.Sprintf("c%d-%08d", aw.config.ClientIDOffset, aw.created.Add(1)))
+	value, _ := aw.values.Generate(w.Rand)
+	metric.TxnKeys = 1
+	start := time.Now()
+	resp, err := w.Client.Txn(ctx).
+		If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+		Then(clientv3.OpPut(key, string(value))).
+		Commit()
+	if err != nil {
+		return key, err
+	}
+	if !resp.Succeeded {
+		return key, errObjectExists
+	}
+	aw.awaitEvent(resp.Header.Revision, start)
+	aw.keys.Insert(key)
+	metric.RequestBytes = int64(len(key) + len(value))
+	return key, nil
+}
+
+// event writes an event about the given object, the event is attached to
+// the shared lease of the events, so that it expires after the event TTL.
+// Like in Kubernetes the event is named after the object and the time.
+func (aw *APIServerWorkload) event(ctx context.Context, w *Worker, key string, metric *KVMetric) (string, error) {
+	namespace := "default"
+	resource, err := generator.ObjectResource(key)
+	if err == nil && !generator.IsClusterScoped(resource) {
+		prefix, _ := generator.ObjectPrefix(key, true)
+		namespace = prefix[len(generator.ObjectKeyPrefix)+len(resource)+1 : len(prefix)-1]
+	}
+	key = generator.ObjectKey(eventResource, namespace, fmt.Sprintf("%s.%x", path.Base(key), time.Now().UnixNano()))
+	lease, err := aw.eventLeaseID(ctx, w.Client)
+	if err != nil {
+		return key, err
+	}
+	value, _ := aw.values.Generate(w.Rand)
+	start := time.Now()
+	resp, err := w.Client.Put(ctx, key, string(value), clientv3.WithLease(lease))
+	if err != nil {
+		return key, err
+	}
+	aw.awaitEvent(resp.Header.Revision, start)
+	metric.RequestBytes = int64(len(key) + len(value))
+	return key, nil
+}
+
+// eventLeaseID returns the lease of the events, a new lease is granted once
+// the current one was used for the reuse duration. The lease lives for the
+// event TTL beyond its last use.
+func (aw *APIServerWorkload) eventLeaseID(ctx context.Context, cli *clientv3.Client) (clientv3.LeaseID, error) {
+	aw.leaseMu.Lock()
+	defer aw.leaseMu.Unlock()
+	if aw.eventLease != 0 && time.Since(aw.eventLeaseAt) < eventLeaseReuse {
+		return aw.eventLease, nil
+	}
+	resp, err := cli.Grant(ctx, aw.config.K8sEventTTL+int64(eventLeaseReuse.Seconds()))
+	if err != nil {
+		return 0, err
+	}
+	aw.eventLease, aw.eventLeaseAt = resp.ID, time.Now()
+	return resp.ID, nil
+}
+
+// awaitEvent remembers the start of a write, so that the latency of its
+// watch events can be measured. Events which were received before are
+// recorded right away.
+func (aw *APIServerWorkload) awaitEvent(rev int64, start time.Time) {
+	if aw.config.K8sWatchesPerResource == 0 {
+		return
+	}
+	aw.writeMu.Lock()
+	write, ok := aw.writes[rev]
+	if !ok {
+		aw.writes[rev] = &writeRecord{start: start, pending: aw.config.K8sWatchesPerResource, added: time.Now()}
+		aw.writeMu.Unlock()
+		return
+	}
+	early := write.early
+	write.start, write.early = start, nil
+	write.pending = aw.config.K8sWatchesPerResource - len(early)
+	if write.pending <= 0 {
+		delete(aw.writes, rev)
+	}
+	aw.writeMu.Unlock()
+	for _, ev := range early {
+		aw.recordEvent(ev, start)
+	}
+}
+
+// receivedEvent matches an event received by a watch with its write. The
+// event is kept if the write did not return yet, which also holds for the
+// writes of other benchmark clients until they expire.
+func (aw *APIServerWorkload) receivedEvent(ev watchEvent) {
+	aw.writeMu.Lock()
+	write, ok := aw.writes[ev.rev]
+	if !ok {
+		aw.writes[ev.rev] = &writeRecord{early: []watchEvent{ev}, added: time.Now()}
+		aw.writeMu.Unlock()
+		return
+	}
+	if write.start.IsZero() {
+		write.early = append(write.early, ev)
+		aw.writeMu.Unlock()
+		return
+	}
+	write.pending--
+	if write.pending == 0 {
+		delete(aw.writes, ev.rev)
+	}
+	aw.writeMu.Unlock()
+	aw.recordEvent(ev, write.start)
+}
+
+// recordEvent records the receipt of a watch event, its latency is the time
+// from the start of the write to the receipt of the event
+func (aw *APIServerWorkload) recordEvent(ev watchEvent, writeStart time.Time) {
+	aw.recordMetric(&KVMetric{
+		RequestMetric: &RequestMetric{
+			Timestamp: ev.recv,
+			Key:       ev.key,
+			Operation: "watch",
+			Latency:   ev.recv.Sub(writeStart),
+			Success:   true,
+		},
+		ResponseBytes: ev.size,
+		Revision:      ev.rev,
+	})
+}
+
+// expireWrites drops the writes whose events did not arrive within the max
+// wait time and the events of writes of other benchmark clients
+func (aw *APIServerWorkload) expireWrites(ctx context.Context) {
+	maxWait := time.Duration(aw.config.MaxWaitTime)
+	ticker := time.NewTicker(maxWait)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			aw.writeMu.Lock()
+			for rev, write := range aw.writes {
+				if now.Sub(write.added) > maxWait {
+					delete(aw.writes, rev)
+				}
+			}
+			aw.writeMu.Unlock()
+		}
+	}
+}
+
+// watch watches the objects of a resource type like the watch cache of the
+// kube-apiserver. Every event of a write of the workload is recorded as an
+// operation. A failed watch, e.g. because its revision was
+// compacted, is recorded as an error and restarted at the latest revision.
+func (aw *APIServerWorkload) watch(ctx context.Context, prefix string) {
+	for ctx.Err() == nil {
+		watchCtx, cancel := context.WithCancel(clientv3.WithRequireLeader(ctx))
+		start := time.Now()
+		var err error
+		for resp := range aw.bgClient.Watch(watchCtx, prefix, clientv3.WithPrefix()) {
+			if err = resp.Err(); err != nil {
+				break
+			}
+			recvTime := time.Now()
+			for _, ev := range resp.Events {
+				aw.receivedEvent(watchEvent{
+					key:  string(ev.Kv.Key),
+					size: int64((*mvccpb.Event)(ev).Size()),
+					rev:  ev.Kv.ModRevision,
+					recv: recvTime,
+				})
+			}
+		}
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			err = errWatchClosed
+		}
+		metric := &KVMetric{
+			RequestMetric: &RequestMetric{
+				Timestamp: time.Now(),
+				Key:       prefix,
+				Operation: "watch",
+				Latency:   time.Since(start),
+				Err:       err,
+			},
+		}
+		metric.StatusCode, metric.StatusText = GetErrInfo(err)
+		aw.recordMetric(metric)
+	}
+}
+
+// compact compacts the revisions like the compactor of the kube-apiserver:
+// every compaction interval the revision seen at the previous interval is
+// compacted. The version of the compact key makes sure that only one of
+// several compactors compacts per interval.
+func (aw *APIServerWorkload) compact(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(aw.config.K8sCompactionInterval))
+	defer ticker.Stop()
+	var version, rev int64
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		start := time.Now()
+		timeoutCtx, cancel := context.WithTimeout(ctx, time.Duration(aw.config.MaxWaitTime))
+		resp, err := aw.bgClient.Txn(timeoutCtx).
+			If(clientv3.Compare(clientv3.Version(compactRevKey), "=", version)).
+			Then(clientv3.OpPut(compactRevKey, strconv.FormatInt(rev, 10))).
+			Else(clientv3.OpGet(compactRevKey)).
+			Commit()
+		if err != nil {
+			cancel()
+			aw.recordCompaction(start, rev, err)
+			continue
+		}
+		compactRev := rev
+		rev = resp.Header.Revision
+		if !resp.Succeeded {
+			// another compactor compacted in this interval
+			if kvs := resp.Responses[0].GetResponseRange().GetKvs(); len(kvs) > 0 {
+				version = kvs[0].Version
+			}
+			cancel()
+			continue
+		}
+		version++
+		if compactRev == 0 {
+			cancel()
+			continue
+		}
+		_, err = aw.bgClient.Compact(timeoutCtx, compactRev)
+		cancel()
+		aw.recordCompaction(start, compactRev, err)
+	}
+}
+
+// recordCompaction records a compaction of the revisions up to the given one
+func (aw *APIServerWorkload) recordCompaction(start time.Time, rev int64, err error) {
+	metric := &KVMetric{
+		RequestMetric: &RequestMetric{
+			Timestamp: time.Now(),
+			Key:       compactRevKey,
+			Operation: "compact",
+			Latency:   time.Since(start),
+			Success:   err == nil,
+			Err:       err,
+		},
+		Revision: rev,
+	}
+	if err != nil {
+		metric.StatusCode, metric.StatusText = GetErrInfo(err)
+	}
+	aw.recordMetric(metric)
+}
@@ -0,0 +1,418 @@
+package runner
+
+import (
+	"context"
+	"csb/control/constants"
+	generator "csb/data-generator"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+func init() {
+	for _, workloadType := range []string{
+		constants.WORKLOAD_TYPE_READ_HEAVY,
+		constants.WORKLOAD_TYPE_UPDATE_HEAVY,
+		constants.WORKLOAD_TYPE_READ_ONLY,
+		constants.WORKLOAD_TYPE_TXN_CAS,
+		constants.WORKLOAD_TYPE_TXN_MULTI,
+		constants.WORKLOAD_TYPE_READ_COMPARE,
+		constants.WORKLOAD_TYPE_MVCC_HISTORY,
+		constants.WORKLOAD_TYPE_LARGE_OBJECT,
+		constants.WORKLOAD_TYPE_YCSB_A,
+		constants.WORKLOAD_TYPE_YCSB_B,
+		constants.WORKLOAD_TYPE_YCSB_C,
+		constants.WORKLOAD_TYPE_YCSB_D,
+		constants.WORKLOAD_TYPE_YCSB_E,
+		constants.WORKLOAD_TYPE_YCSB_F,
+	} {
+		Register(constants.SCENARIO_KV_STORE, workloadType, NewKVWorkload)
+	}
+}
+
+// Levels picked by range reads at the "mixed" range level
+var rangeLevels = []string{
+	constants.RANGE_LEVEL_DOMAIN,
+	constants.RANGE_LEVEL_REGION,
+	constants.RANGE_LEVEL_SHARD,
+}
+
+// kvOperation is an operation of the operation mix with its parameters
+// resolved against the config
+type kvOperation struct {
+	name        string
+	weight      int
+	rangeLevel  string
+	rangeMode   string
+	rangeLimit  int64
+	scanLength  int
+	txnKeys     int
+	revisionLag int64
+	depth       int    // history depth
+	consistency string // read consistency of get, range and scan
+}
+
+// KVWorkload runs a weighted mix of operations on random keys, the mix is
+// either configured or the preset of the workload type
+type KVWorkload struct {
+	config      *BenchmarkRunConfig
+	generator   *generator.Generator
+	values      *generator.ValueGenerator
+	operations  []kvOperation
+	totalWeight int
+	// live keys, inserts and deletes change them during the run
+	keys *KeySpace
+	// read of every worker which is still to be repeated with the other
+	// read consistency by the read-compare workload type
+	pendingReads map[int]pendingRead
+	mu           sync.Mutex
+	// latest revision seen in the responses of etcd
+	revision atomic.Int64
+}
+
+// pendingRead is the second read of a pair of reads of the same key
+type pendingRead struct {
+	key         string
+	consistency string
+}
+
+func NewKVWorkload(env *WorkloadEnv) (Workload, error) {
+	mix, err := GetOperationMix(env.Config)
+	if err != nil {
+		return nil, err
+	}
+	kv := &KVWorkload{
+		config:       env.Config,
+		generator:    env.Generator,
+		values:       env.Values,
+		pendingReads: make(map[int]pendingRead),
+	}
+	for _, op := range mix {
+		o := kvOperation{
+			name:        op.Name,
+			weight:      op.Weight,
+			rangeLevel:  env.Config.RangeLevel,
+			rangeMode:   env.Config.RangeMode,
+			rangeLimit:  env.Config.RangeLimit,
+			scanLength:  env.Config.ScanLength,
+			txnKeys:     env.Config.TxnKeys,
+			revisionLag: env.Config.RevisionLag,
+			depth:       env.Config.HistoryDepth,
+			consistency: env.Config.ReadConsistency,
+		}
+		// the parameters are validated by the control program
+		if level, ok := op.Params["level"]; ok {
+			o.rangeLevel = level
+		}
+		if mode, ok := op.Params["mode"]; ok {
+			o.rangeMode = mode
+		}
+		if limit, ok := op.Params["limit"]; ok {
+			o.rangeLimit, _ = strconv.ParseInt(limit, 10, 64)
+		}
+		if length, ok := op.Params["length"]; ok {
+			o.scanLength, _ = strconv.Atoi(length)
+		}
+		if keys, ok := op.Params["keys"]; ok {
+			o.txnKeys, _ = strconv.Atoi(keys)
+		}
+		if lag, ok := op.Params["lag"]; ok {
+			o.revisionLag, _ = strconv.ParseInt(lag, 10, 64)
+		}
+		if depth, ok := op.Params["depth"]; ok {
+			o.depth, _ = strconv.Atoi(depth)
+		}
+		if consistency, ok := op.Params["consistency"]; ok {
+			o.consistency = consistency
+		}
+		kv.operations = append(kv.operations, o)
+		kv.totalWeight += o.weight
+	}
+	return kv, nil
+}
+
+func (kv *KVWorkload) Setup(ctx context.Context) error {
+	for _, op := range kv.operations {
+		if op.name == constants.OPERATION_TXN && len(kv.config.Keys) < op.txnKeys {
+			return fmt.Errorf("transactions of %d keys need at least as many keys, got %d", op.txnKeys, len(kv.config.Keys))
+		}
+	}
+	kv.keys = NewKeySpace(kv.config.Keys, kv.config.KeySize)
+	return nil
+}
+
+// KeySpace returns the live keys of the workload
+func (kv *KVWorkload) KeySpace() *KeySpace {
+	return kv.keys
+}
+
+func (kv *KVWorkload) Teardown() error {
+	return nil
+}
+
+func (kv *KVWorkload) MetricHeader() []string {
+	return (&KVMetric{RequestMetric: &RequestMetric{}}).ToCSVHeader()
+}
+
+// nextOperation picks an operation of the mix according to the weights
+func (kv *KVWorkload) nextOperation(rg *rand.Rand) *kvOperation {
+	n := rg.Intn(kv.totalWeight)
+	for i := range kv.operations {
+		if n < kv.operations[i].weight {
+			return &kv.operations[i]
+		}
+		n -= kv.operations[i].weight
+	}
+	return &kv.operations[len(kv.operations)-1]
+}
+
+func (kv *KVWorkload) Execute(ctx context.Context, w *Worker) Metric {
+	op := kv.nextOperation(w.Rand)
+	if op.name == constants.OPERATION_TXN {
+		return kv.txn(ctx, w, op.txnKeys)
+	}
+
+	// Select the key from the live keys following the key distribution
+	index := w.Keys.Next(w.Rand, kv.keys.Len())
+	key := kv.keys.Key(index)
+	var consistency string
+	if op.name == constants.OPERATION_GET || op.name == constants.OPERATION_RANGE || op.name == constants.OPERATION_SCAN {
+		consistency = op.readConsistency(w.Rand, kv.config.SerializablePercent)
+		if kv.config.WorkloadType == constants.WORKLOAD_TYPE_READ_COMPARE {
+			key, consistency = kv.pairRead(w, key)
+		}
+	}
+	readOpts := []clientv3.OpOption{}
+	if consistency == constants.READ_CONSISTENCY_SERIALIZABLE {
+		readOpts = append(readOpts, clientv3.WithSerializable())
+	}
+	requestTimeout := time.Duration(kv.config.MaxWaitTime)
+	timeoutCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	var (
+		err                                      error
+		keysScanned, requestBytes, responseBytes int64
+		mvcc                                     mvccResult
+		// size of the value written or read, -1 if unknown
+		valueSize = -1
+	)
+	operation := op.name
+	switch op.name {
+	case constants.OPERATION_GET_REV:
+		mvcc, err = kv.getAtRevision(timeoutCtx, w, key, op.revisionLag)
+	case constants.OPERATION_PUT_PREVKV:
+		newVal, _ := kv.values.Generate(w.Rand)
+		mvcc, err = kv.putPrevKV(timeoutCtx, w, key, string(newVal))
+		if err == nil {
+			requestBytes = int64(len(key) + len(newVal))
+		}
+	case constants.OPERATION_HISTORY:
+		mvcc, err = kv.walkHistory(timeoutCtx, w, key, op.depth)
+	case constants.OPERATION_RANGE:
+		key, err = generator.KeyPrefix(key, op.level(w.Rand))
+		if err == nil {
+			keysScanned, responseBytes, err = kv.rangeRead(timeoutCtx, w.Client, key, op, readOpts)
+		}
+	case constants.OPERATION_GET:
+		operation = "read"
+		var resp *clientv3.GetResponse
+		resp, err = w.Client.Get(timeoutCtx, key, readOpts...)
+		if err == nil {
+			kv.observeRevision(resp.Header)
+			keysScanned = int64(len(resp.Kvs))
+			responseBytes = int64((*etcdserverpb.RangeResponse)(resp).Size())
+			if len(resp.Kvs) > 0 {
+				valueSize = len(resp.Kvs[0].Value)
+			}
+		}
+	case constants.OPERATION_SCAN:
+		// the keys following the key in sort order like a YCSB scan
+		var resp *clientv3.GetResponse
+		length := 1 + w.Rand.Intn(op.scanLength)
+		resp, err = w.Client.Get(timeoutCtx, key, append(readOpts, clientv3.WithFromKey(), clientv3.WithLimit(int64(length)))...)
+		if err == nil {
+			keysScanned = int64(len(resp.Kvs))
+			responseBytes = int64((*etcdserverpb.RangeResponse)(resp).Size())
+		}
+	case constants.OPERATION_RMW:
+		// the latency covers the read and the write, the value written back
+		// is a new one like in YCSB
+		var getResp *clientv3.GetResponse
+		getResp, err = w.Client.Get(timeoutCtx, key)
+		if err == nil {
+			keysScanned = int64(len(getResp.Kvs))
+			responseBytes = int64((*etcdserverpb.RangeResponse)(getResp).Size())
+			newVal, _ := kv.values.Generate(w.Rand)
+			var putResp *clientv3.PutResponse
+			putResp, err = w.Client.Put(timeoutCtx, key, string(newVal))
+			if err == nil {
+				requestBytes = int64(len(key) + len(newVal))
+				responseBytes += int64((*etcdserverpb.PutResponse)(putResp).Size())
+			}
+		}
+	case constants.OPERATION_DELETE:
+		// no other worker picks the key while it is deleted, it leaves the
+		// key space once the delete succeeded
+		var deleted string
+		deleted, err = kv.keys.Delete(index)
+		if err == nil {
+			key = deleted
+			var resp *clientv3.DeleteResponse
+			resp, err = w.Client.Delete(timeoutCtx, key)
+			kv.keys.FinishDelete(key, err == nil)
+			if err == nil {
+				keysScanned = resp.Deleted
+				responseBytes = int64((*etcdserverpb.DeleteRangeResponse)(resp).Size())
+			}
+		}
+	case constants.OPERATION_INSERT:
+		// the key joins the key space once it is written
+		key, err = kv.keys.NewKey(w.Rand)
+		if err == nil {
+			newVal, _ := kv.values.Generate(w.Rand)
+			var resp *clientv3.PutResponse
+			resp, err = w.Client.Put(timeoutCtx, key, string(newVal))
+			if err == nil {
+				kv.keys.Insert(key)
+				requestBytes = int64(len(key) + len(newVal))
+				responseBytes = int64((*etcdserverpb.PutResponse)(resp).Size())
+			}
+		}
+	default:
+		operation = "write"
+		newVal, _ := kv.values.Generate(w.Rand)
+		valueSize = len(newVal)
+		var resp *clientv3.PutResponse
+		resp, err = w.Client.Put(timeoutCtx, key, string(newVal))
+		if err == nil {
+			kv.observeRevision(resp.Header)
+			requestBytes = int64(len(key) + len(newVal))
+			responseBytes = int64((*etcdserverpb.PutResponse)(resp).Size())
+		}
+	}
+	latency := time.Since(w.Start)
+
+	metric := &KVMetric{
+		RequestMetric: &RequestMetric{
+			Timestamp: time.Now(),
+			Key:       key,
+			Operation: operation,
+			Latency:   latency,
+			Success:   err == nil,
+			Err:       err,
+		},
+		KeysScanned:   keysScanned + mvcc.keysScanned,
+		RequestBytes:  requestBytes,
+		ResponseBytes: responseBytes + mvcc.responseBytes,
+		Consistency:   consistency,
+		Revision:      mvcc.revision,
+		RevisionLag:   mvcc.revisionLag,
+	}
+	if kv.config.WorkloadType == constants.WORKLOAD_TYPE_LARGE_OBJECT && valueSize >= 0 {
+		metric.SizeClass = sizeClass(valueSize)
+	}
+	if err != nil {
+		metric.StatusCode, metric.StatusText = GetErrInfo(err)
+	}
+	return metric
+}
+
+// readConsistency returns the read consistency of a read, with "mixed" the
+// serializable percent of the reads are serializable
+func (op *kvOperation) readConsistency(rg *rand.Rand, serializablePercent int) string {
+	switch op.consistency {
+	case constants.READ_CONSISTENCY_SERIALIZABLE:
+		return constants.READ_CONSISTENCY_SERIALIZABLE
+	case constants.READ_CONSISTENCY_MIXED:
+		if rg.Intn(100) < serializablePercent {
+			return constants.READ_CONSISTENCY_SERIALIZABLE
+		}
+	}
+	return constants.READ_CONSISTENCY_LINEARIZABLE
+}
+
+// pairRead returns the key and the read consistency of the next read of the
+// read-compare workload type. Every key is read twice in a row by the same
+// worker, once with each read consistency in random order, so that both
+// kinds of reads see the same keys.
+func (kv *KVWorkload) pairRead(w *Worker, key string) (string, string) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	if pending, ok := kv.pendingReads[w.ID]; ok {
+		delete(kv.pendingReads, w.ID)
+		return pending.key, pending.consistency
+	}
+	first, second := constants.READ_CONSISTENCY_LINEARIZABLE, constants.READ_CONSISTENCY_SERIALIZABLE
+	if w.Rand.Intn(2) == 0 {
+		first, second = second, first
+	}
+	kv.pendingReads[w.ID] = pendingRead{key: key, consistency: second}
+	return key, first
+}
+
+// level returns the level of the key hierarchy scanned by a range read, the
+// shard level if no level is configured
+func (op *kvOperation) level(rg *rand.Rand) string {
+	switch op.rangeLevel {
+	case "":
+		return constants.RANGE_LEVEL_SHARD
+	case constants.RANGE_LEVEL_MIXED:
+		return rangeLevels[rg.Intn(len(rangeLevels))]
+	}
+	return op.rangeLevel
+}
+
+// rangeRead scans all keys with the given prefix and returns the number of
+// keys and the size of the responses. With a range limit the keys are read
+// page by page at the revision of the first page, like the paginated list
+// calls of Kubernetes.
+func (kv *KVWorkload) rangeRead(ctx context.Context, cli *clientv3.Client, prefix string, op *kvOperation, readOpts []clientv3.OpOption) (int64, int64, error) {
+	opts := append([]clientv3.OpOption{clientv3.WithRange(clientv3.GetPrefixRangeEnd(prefix))}, readOpts...)
+	switch op.rangeMode {
+	case constants.RANGE_MODE_COUNT_ONLY:
+		// the count is returned in a single response
+		resp, err := cli.Get(ctx, prefix, append(opts, clientv3.WithCountOnly())...)
+		if err != nil {
+			return 0, 0, err
+		}
+		return resp.Count, int64((*etcdserverpb.RangeResponse)(resp).Size()), nil
+	case constants.RANGE_MODE_KEYS_ONLY:
+		opts = append(opts, clientv3.WithKeysOnly())
+	}
+	return pagedRange(ctx, cli, prefix, op.rangeLimit, opts)
+}
+
+// pagedRange reads the range of the options starting at the given key, with
+// a limit the range is read in pages of that size at the revision of the
+// first page. It returns the number of keys and the size of the responses.
+func pagedRange(ctx context.Context, cli *clientv3.Client, key string, limit int64, opts []clientv3.OpOption) (int64, int64, error) {
+	if limit > 0 {
+		opts = append(opts, clientv3.WithLimit(limit))
+	}
+
+	var keysScanned, responseBytes int64
+	for rev := int64(0); ; {
+		resp, err := cli.Get(ctx, key, opts...)
+		if err != nil {
+			return keysScanned, responseBytes, err
+		}
+		keysScanned += int64(len(resp.Kvs))
+		responseBytes += int64((*etcdserverpb.RangeResponse)(resp).Size())
+		if !resp.More || len(resp.Kvs) == 0 {
+			return keysScanned, responseBytes, nil
+		}
+		// the next page starts right after the last key of this page
+		key = string(resp.Kvs[len(resp.Kvs)-1].Key) + "\x00"
+		if rev == 0 {
+			rev = resp.Header.Revision
+			opts = append(opts, clientv3.WithRev(rev))
+		}
+	}
+}
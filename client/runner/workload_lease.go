@@ -0,0 +1,451 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	lg "csb/client/logger"
+	"csb/control/constants"
+	generator "csb/data-generator"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"google.golang.org/grpc"
+)
+
+// errKeepAliveStopped is returned when the keepalive stream stops renewing a
+// lease, because it expired or its renewal was not answered in time
+var errKeepAliveStopped = errors.New("keepalive of the lease stopped")
+
+func init() {
+	for _, workloadType := range []string{
+		constants.WORKLOAD_TYPE_LEASE_GRANT_REVOKE,
+		constants.WORKLOAD_TYPE_LEASE_KEEPALIVE,
+		constants.WORKLOAD_TYPE_LEASE_KEEPALIVE_STREAM,
+		constants.WORKLOAD_TYPE_LEASE_EXPIRE,
+	} {
+		Register(constants.SCENARIO_LEASE, workloadType, NewLeaseWorkload)
+	}
+}
+
+// leaseHolder holds the leases a worker keeps alive
+type leaseHolder struct {
+	client *clientv3.Client
+	leases []clientv3.LeaseID
+	next   int // index of the lease renewed next
+
+	// Keepalive stream of the worker in the lease-keepalive-stream workload
+	lessor   clientv3.Lease
+	timed    *timedLeaseClient
+	renewals chan renewal // renewals of all leases of the worker
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+// renewal is a response on the keepalive stream of a worker
+type renewal struct {
+	leaseID clientv3.LeaseID
+	ttl     int64
+	latency time.Duration
+	err     error
+}
+
+// LeaseWorkload grants leases with a key attached to each of them, then
+// revokes them, keeps them alive with single requests or a stream, or lets
+// them expire
+type LeaseWorkload struct {
+	config    *BenchmarkRunConfig
+	generator *generator.Generator
+	values    *generator.ValueGenerator
+	logger    *lg.Logger
+
+	// Leases of every worker in the keepalive workloads
+	holders map[int]*leaseHolder
+	mu      sync.Mutex
+}
+
+func NewLeaseWorkload(env *WorkloadEnv) (Workload, error) {
+	return &LeaseWorkload{
+		config:    env.Config,
+		generator: env.Generator,
+		values:    env.Values,
+		logger:    env.Logger,
+		holders:   make(map[int]*leaseHolder),
+	}, nil
+}
+
+func (l *LeaseWorkload) Setup(ctx context.Context) error {
+	return nil
+}
+
+// PrepareStep closes the keepalive streams of the workers which are not part
+// of the step, their leases expire, and drops the renewals received between
+// the steps
+func (l *LeaseWorkload) PrepareStep(numWorkers int) error {
+	if l.config.WorkloadType != constants.WORKLOAD_TYPE_LEASE_KEEPALIVE_STREAM {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for id, holder := range l.holders {
+		if id >= numWorkers {
+			holder.close()
+			delete(l.holders, id)
+			continue
+		}
+		holder.dropRenewals()
+	}
+	return nil
+}
+
+// Teardown revokes the leases which are still kept alive
+func (l *LeaseWorkload) Teardown() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var lastErr error
+	for id, holder := range l.holders {
+		holder.close()
+		for _, leaseID := range holder.leases {
+			ctx, cancel := GetTimeoutCtx(time.Duration(l.config.MaxWaitTime))
+			_, err := holder.client.Revoke(ctx, leaseID)
+			cancel()
+			if err != nil && !errors.Is(err, rpctypes.ErrLeaseNotFound) {
+				lastErr = fmt.Errorf("failed to revoke lease %x: %w", leaseID, err)
+			}
+		}
+		delete(l.holders, id)
+	}
+	return lastErr
+}
+
+func (l *LeaseWorkload) MetricHeader() []string {
+	return (&LeaseMetric{RequestMetric: &RequestMetric{}}).ToCSVHeader()
+}
+
+func (l *LeaseWorkload) Execute(ctx context.Context, w *Worker) Metric {
+	metric := &LeaseMetric{RequestMetric: &RequestMetric{}}
+	var err error
+	switch l.config.WorkloadType {
+	case constants.WORKLOAD_TYPE_LEASE_KEEPALIVE:
+		err = l.keepAlive(ctx, w, metric)
+	case constants.WORKLOAD_TYPE_LEASE_KEEPALIVE_STREAM:
+		err = l.keepAliveStream(ctx, w, metric)
+	case constants.WORKLOAD_TYPE_LEASE_EXPIRE:
+		err = l.expire(ctx, w, metric)
+	default:
+		err = l.grantRevoke(ctx, w, metric)
+	}
+	// the latency of an expiry is the delay of its detection, the one of a
+	// streamed renewal the time from its request to its response
+	if (metric.Operation != "expire" && metric.Operation != "keepalive-stream") || err != nil {
+		metric.Latency = time.Since(w.Start)
+	}
+
+	metric.Timestamp = time.Now()
+	metric.Success = err == nil
+	metric.Err = err
+	if err != nil {
+		metric.StatusCode, metric.StatusText = GetErrInfo(err)
+	}
+	return metric
+}
+
+// grant grants a lease and attaches a key to it, the key is derived from a
+// random key and the lease ID, so that it is not shared with other leases
+func (l *LeaseWorkload) grant(ctx context.Context, w *Worker, metric *LeaseMetric) (*clientv3.PutResponse, error) {
+	resp, err := w.Client.Grant(ctx, l.config.LeaseTTL)
+	metric.GrantLatency = time.Since(w.Start)
+	if err != nil {
+		return nil, err
+	}
+	metric.LeaseID, metric.TTL = int64(resp.ID), resp.TTL
+	metric.Key = fmt.Sprintf("/lease%s/%x", l.config.Keys[w.Keys.Next(w.Rand, len(l.config.Keys))], resp.ID)
+	value, _ := l.values.Generate(w.Rand)
+	return w.Client.Put(ctx, metric.Key, string(value), clientv3.WithLease(resp.ID))
+}
+
+// grantRevoke grants a lease, attaches a key and revokes the lease again
+func (l *LeaseWorkload) grantRevoke(ctx context.Context, w *Worker, metric *LeaseMetric) error {
+	metric.Operation = "grant-revoke"
+	timeoutCtx, cancel := context.WithTimeout(ctx, time.Duration(l.config.MaxWaitTime))
+	defer cancel()
+
+	_, err := l.grant(timeoutCtx, w, metric)
+	if metric.LeaseID == 0 {
+		return err
+	}
+	// the lease is revoked even if the key could not be attached
+	revokeStart := time.Now()
+	_, revokeErr := w.Client.Revoke(timeoutCtx, clientv3.LeaseID(metric.LeaseID))
+	metric.RevokeLatency = time.Since(revokeStart)
+	if err == nil {
+		err = revokeErr
+	}
+	return err
+}
+
+// keepAlive renews the leases of the worker one after another, until the
+// worker holds the configured number of leases it grants a new one instead
+func (l *LeaseWorkload) keepAlive(ctx context.Context, w *Worker, metric *LeaseMetric) error {
+	timeoutCtx, cancel := context.WithTimeout(ctx, time.Duration(l.config.MaxWaitTime))
+	defer cancel()
+
+	holder := l.getHolder(w)
+	if len(holder.leases) < l.config.LeasesPerClient {
+		metric.Operation = "grant"
+		_, err := l.grant(timeoutCtx, w, metric)
+		if metric.LeaseID != 0 {
+			holder.leases = append(holder.leases, clientv3.LeaseID(metric.LeaseID))
+		}
+		return err
+	}
+
+	metric.Operation = "keepalive"
+	holder.next %= len(holder.leases)
+	leaseID := holder.leases[holder.next]
+	metric.LeaseID = int64(leaseID)
+	resp, err := w.Client.KeepAliveOnce(timeoutCtx, leaseID)
+	metric.KeepAliveLatency = time.Since(w.Start)
+	if errors.Is(err, rpctypes.ErrLeaseNotFound) {
+		// the lease expired while the worker was not part of a step, it is
+		// replaced by a new one
+		holder.leases = append(holder.leases[:holder.next], holder.leases[holder.next+1:]...)
+		return err
+	}
+	holder.next++
+	if err != nil {
+		return err
+	}
+	metric.TTL = resp.TTL
+	return nil
+}
+
+// keepAliveStream keeps the leases of the worker alive with the keepalive
+// stream of the worker, until the worker holds the configured number of
+// leases it grants a new one, otherwise it records the next renewal
+func (l *LeaseWorkload) keepAliveStream(ctx context.Context, w *Worker, metric *LeaseMetric) error {
+	holder := l.getHolder(w)
+	if len(holder.leases) < l.config.LeasesPerClient {
+		metric.Operation = "grant"
+		timeoutCtx, cancel := context.WithTimeout(ctx, time.Duration(l.config.MaxWaitTime))
+		defer cancel()
+		_, err := l.grant(timeoutCtx, w, metric)
+		if metric.LeaseID == 0 {
+			return err
+		}
+		// the lease is kept alive even if the key could not be attached
+		leaseID := clientv3.LeaseID(metric.LeaseID)
+		holder.leases = append(holder.leases, leaseID)
+		if kaErr := holder.keepAlive(leaseID); err == nil {
+			err = kaErr
+		}
+		return err
+	}
+
+	metric.Operation = "keepalive-stream"
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case r := <-holder.renewals:
+		metric.LeaseID = int64(r.leaseID)
+		if r.err != nil {
+			// the lease is replaced by a new one
+			holder.remove(r.leaseID)
+			return r.err
+		}
+		metric.TTL = r.ttl
+		metric.KeepAliveLatency = r.latency
+		metric.Latency = r.latency
+		return nil
+	}
+}
+
+func (l *LeaseWorkload) getHolder(w *Worker) *leaseHolder {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	holder, ok := l.holders[w.ID]
+	if !ok {
+		holder = &leaseHolder{client: w.Client}
+		if l.config.WorkloadType == constants.WORKLOAD_TYPE_LEASE_KEEPALIVE_STREAM {
+			holder.timed = &timedLeaseClient{
+				LeaseClient: clientv3.RetryLeaseClient(w.Client),
+				sent:        make(map[int64]time.Time),
+				latencies:   make(map[int64]time.Duration),
+			}
+			holder.lessor = clientv3.NewLeaseFromLeaseClient(holder.timed, w.Client, time.Duration(l.config.MaxWaitTime))
+			holder.renewals = make(chan renewal, l.config.LeasesPerClient)
+			holder.ctx, holder.cancel = context.WithCancel(context.Background())
+		}
+		l.holders[w.ID] = holder
+	}
+	return holder
+}
+
+// keepAlive adds a lease to the keepalive stream of the worker and forwards
+// its renewals, the lessor sends a renewal every third of the TTL
+func (h *leaseHolder) keepAlive(leaseID clientv3.LeaseID) error {
+	ch, err := h.lessor.KeepAlive(h.ctx, leaseID)
+	if err != nil {
+		h.remove(leaseID)
+		return err
+	}
+	go func() {
+		for resp := range ch {
+			r := renewal{leaseID: leaseID, ttl: resp.TTL, latency: h.timed.latency(leaseID)}
+			select {
+			case h.renewals <- r:
+			case <-h.ctx.Done():
+				return
+			}
+		}
+		select {
+		case h.renewals <- renewal{leaseID: leaseID, err: errKeepAliveStopped}:
+		case <-h.ctx.Done():
+		}
+	}()
+	return nil
+}
+
+// remove removes a lease which is no longer kept alive
+func (h *leaseHolder) remove(leaseID clientv3.LeaseID) {
+	h.leases = slices.DeleteFunc(h.leases, func(id clientv3.LeaseID) bool { return id == leaseID })
+	if h.timed != nil {
+		h.timed.forget(leaseID)
+	}
+}
+
+// dropRenewals drops the pending renewals, the leases whose keepalive
+// stopped are removed
+func (h *leaseHolder) dropRenewals() {
+	for {
+		select {
+		case r := <-h.renewals:
+			if r.err != nil {
+				h.remove(r.leaseID)
+			}
+		default:
+			return
+		}
+	}
+}
+
+// close stops the keepalive stream of the worker
+func (h *leaseHolder) close() {
+	if h.lessor == nil {
+		return
+	}
+	h.cancel()
+	h.lessor.Close()
+}
+
+// timedLeaseClient measures the latency of every keepalive request on the
+// streams of a lessor, from the send of the request to the receipt of its
+// response
+type timedLeaseClient struct {
+	pb.LeaseClient
+	mu        sync.Mutex
+	sent      map[int64]time.Time     // send time of the unanswered request of every lease
+	latencies map[int64]time.Duration // latency of the last response of every lease
+}
+
+func (c *timedLeaseClient) LeaseKeepAlive(ctx context.Context, opts ...grpc.CallOption) (pb.Lease_LeaseKeepAliveClient, error) {
+	stream, err := c.LeaseClient.LeaseKeepAlive(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// the requests of a previous stream are not answered anymore
+	c.mu.Lock()
+	clear(c.sent)
+	c.mu.Unlock()
+	return &timedKeepAliveStream{Lease_LeaseKeepAliveClient: stream, client: c}, nil
+}
+
+// latency returns the latency of the last response for a lease
+func (c *timedLeaseClient) latency(leaseID clientv3.LeaseID) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.latencies[int64(leaseID)]
+}
+
+func (c *timedLeaseClient) forget(leaseID clientv3.LeaseID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.sent, int64(leaseID))
+	delete(c.latencies, int64(leaseID))
+}
+
+// timedKeepAliveStream is a keepalive stream which records the send time of
+// the requests and the latency of the responses
+type timedKeepAliveStream struct {
+	pb.Lease_LeaseKeepAliveClient
+	client *timedLeaseClient
+}
+
+// Send records the send time of a request, the lessor sends the request of
+// a lease again if its response is late, the latency is measured from the
+// first one
+func (s *timedKeepAliveStream) Send(req *pb.LeaseKeepAliveRequest) error {
+	s.client.mu.Lock()
+	if _, ok := s.client.sent[req.ID]; !ok {
+		s.client.sent[req.ID] = time.Now()
+	}
+	s.client.mu.Unlock()
+	return s.Lease_LeaseKeepAliveClient.Send(req)
+}
+
+func (s *timedKeepAliveStream) Recv() (*pb.LeaseKeepAliveResponse, error) {
+	resp, err := s.Lease_LeaseKeepAliveClient.Recv()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	s.client.mu.Lock()
+	if sent, ok := s.client.sent[resp.ID]; ok {
+		s.client.latencies[resp.ID] = now.Sub(sent)
+		delete(s.client.sent, resp.ID)
+	}
+	s.client.mu.Unlock()
+	return resp, nil
+}
+
+// expire grants a lease and waits for the deletion of its key once the lease
+// expired, the latency of the operation is the delay between the expiry of
+// the lease and the receipt of the deletion event
+func (l *LeaseWorkload) expire(ctx context.Context, w *Worker, metric *LeaseMetric) error {
+	metric.Operation = "expire"
+	timeoutCtx, cancel := context.WithTimeout(ctx, time.Duration(l.config.MaxWaitTime))
+	defer cancel()
+
+	putResp, err := l.grant(timeoutCtx, w, metric)
+	if err != nil {
+		return err
+	}
+	expiry := w.Start.Add(metric.GrantLatency + time.Duration(metric.TTL)*time.Second)
+
+	// the watch lasts until the end of the step at most
+	watchCtx, watchCancel := context.WithCancel(clientv3.WithRequireLeader(ctx))
+	defer watchCancel()
+	wch := w.Client.Watch(watchCtx, metric.Key, clientv3.WithRev(putResp.Header.Revision+1), clientv3.WithFilterPut())
+	for resp := range wch {
+		if err := resp.Err(); err != nil {
+			return err
+		}
+		for _, ev := range resp.Events {
+			if ev.Type == mvccpb.DELETE {
+				metric.ExpiryDelay = time.Since(expiry)
+				metric.Latency = max(metric.ExpiryDelay, 0)
+				return nil
+			}
+		}
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return errWatchClosed
+}
@@ -0,0 +1,262 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	lg "csb/client/logger"
+	"csb/control/constants"
+	generator "csb/data-generator"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/concurrency"
+)
+
+func init() {
+	for _, workloadType := range []string{
+		constants.WORKLOAD_TYPE_LOCK_ONLY,
+		constants.WORKLOAD_TYPE_LOCK_MIXED_READ,
+		constants.WORKLOAD_TYPE_LOCK_MIXED_WRITE,
+		constants.WORKLOAD_TYPE_LOCK_CONTENTION,
+	} {
+		Register(constants.SCENARIO_LOCK_SERVICE, workloadType, NewLockWorkload)
+	}
+}
+
+// LockWorkload acquires and releases distributed locks, optionally with a
+// read or write operation while holding the lock
+type LockWorkload struct {
+	config    *BenchmarkRunConfig
+	generator *generator.Generator
+	values    *generator.ValueGenerator
+	logger    *lg.Logger
+
+	// Lock-specific configurations
+	lockNames []string // List of available lock names
+	// Session of every etcd client, the sessions are only added between steps
+	sessions map[*clientv3.Client]*concurrency.Session
+	mu       sync.Mutex
+}
+
+func NewLockWorkload(env *WorkloadEnv) (Workload, error) {
+	return &LockWorkload{
+		config:    env.Config,
+		generator: env.Generator,
+		values:    env.Values,
+		logger:    env.Logger,
+		sessions:  make(map[*clientv3.Client]*concurrency.Session),
+	}, nil
+}
+
+func (l *LockWorkload) Setup(ctx context.Context) error {
+	// Generate lock names
+	l.lockNames = make([]string, len(l.config.Keys))
+	for i, key := range l.config.Keys {
+		// since all key starts with / we can use /lock[key]
+		l.lockNames[i] = fmt.Sprintf("/lock%s", key)
+	}
+	return nil
+}
+
+// PrepareClient creates the session used for distributed locking
+func (l *LockWorkload) PrepareClient(cli *clientv3.Client) error {
+	session, err := concurrency.NewSession(cli)
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sessions[cli] = session
+	return nil
+}
+
+func (l *LockWorkload) Teardown() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var lastErr error
+	for cli, session := range l.sessions {
+		if err := session.Close(); err != nil {
+			lastErr = fmt.Errorf("failed to close session: %w", err)
+		}
+		delete(l.sessions, cli)
+	}
+	return lastErr
+}
+
+func (l *LockWorkload) MetricHeader() []string {
+	return (&LockMetric{RequestMetric: &RequestMetric{}}).ToCSVHeader()
+}
+
+func (l *LockWorkload) Execute(ctx context.Context, w *Worker) Metric {
+	// Select lock name based on workload type
+	var lockName string
+	contentionLevel := 0
+	if l.config.WorkloadType == constants.WORKLOAD_TYPE_LOCK_CONTENTION {
+		// Number of clients competing for the same set of locks
+		contentionLevel = max(w.NumClients/2, 1)
+		// Determine a random starting index for the section
+		startIndex := w.Rand.Intn(len(l.lockNames) - contentionLevel + 1) // Ensure the range fits the slice
+		// Use a small subset of locks for higher contention
+		lockName = l.lockNames[startIndex+w.Rand.Intn(contentionLevel)]
+	} else {
+		// Pick a lockname from all available names following the key distribution
+		lockName = l.lockNames[w.Keys.Next(w.Rand, len(l.lockNames))]
+	}
+
+	key := lockName[5:] // Remove "/lock" prefix
+	mutex := concurrency.NewMutex(l.sessions[w.Client], lockName)
+
+	var metric *LockMetric
+	switch l.config.WorkloadType {
+	case constants.WORKLOAD_TYPE_LOCK_MIXED_READ, constants.WORKLOAD_TYPE_LOCK_MIXED_WRITE:
+		metric = l.runLockMixed(mutex, w.Start, w.Client, w.Rand, key)
+	default:
+		metric = l.runLockOnly(mutex, w.Start)
+	}
+	metric.LockName = lockName
+	metric.ContentionLevel = contentionLevel
+	return metric
+}
+
+// Quick acquire-release cycles without any KV operations
+func (l *LockWorkload) runLockOnly(mutex *concurrency.Mutex, start time.Time) *LockMetric {
+	var (
+		acquireLatency, releaseLatency time.Duration
+		success                        bool = false
+		err                            error
+		lockOpStatusCode               int
+		lockOpStatusText               string = ""
+	)
+
+	tryLockCtx, tryLockCtxCancel := GetTimeoutCtx(time.Duration(l.config.MaxWaitTime))
+	defer tryLockCtxCancel()
+	if err = mutex.TryLock(tryLockCtx); err == nil {
+		acquireLatency = time.Since(start)
+		// Release immediately
+		unLockCtx, unLockCtxCancel := GetTimeoutCtx(time.Duration(l.config.MaxWaitTime))
+		defer unLockCtxCancel()
+		releaseStart := time.Now()
+		err = mutex.Unlock(unLockCtx)
+		releaseLatency = time.Since(releaseStart)
+		if err != nil {
+			lockOpStatusCode, lockOpStatusText = GetErrInfo(err)
+			l.logger.Printf("Failed to release the lock: %v", err)
+		}
+		success = true
+	} else if err == concurrency.ErrLocked {
+		l.logger.Printf("Failed to acquire the lock %s, which is held by other session: %v", mutex.Key(), err)
+	} else if err == concurrency.ErrSessionExpired {
+		l.logger.Printf("Failed to acquire the lock, session expired: %v", err)
+	}
+
+	if err != nil && lockOpStatusCode == 0 {
+		lockOpStatusCode, lockOpStatusText = GetErrInfo(err)
+	}
+
+	latency := acquireLatency + releaseLatency
+	if acquireLatency == 0 {
+		// the lock was not acquired, the attempt took the whole time
+		latency = time.Since(start)
+	}
+
+	return &LockMetric{
+		RequestMetric: &RequestMetric{
+			Timestamp: time.Now(),
+			Key:       "",
+			Operation: "lock",
+			Latency:   latency,
+			Success:   success,
+			Err:       err,
+		},
+		AquireLatency:    acquireLatency,
+		ReleaseLatency:   releaseLatency,
+		LockOpStatusCode: lockOpStatusCode,
+		LockOpStatusText: lockOpStatusText,
+	}
+}
+
+// Mixed workload with lock acquisition, read or write, lock release operations
+func (l *LockWorkload) runLockMixed(mutex *concurrency.Mutex, start time.Time, client *clientv3.Client, rg *rand.Rand, key string) *LockMetric {
+	var (
+		acquireLatency, kvLatency, releaseLatency time.Duration
+		success                                   bool = false
+		err                                       error
+		statusCode, lockOpStatusCode              int
+		statusText                                string = ""
+		lockOpStatusText                          string = ""
+		isRead                                    bool   = l.config.WorkloadType == constants.WORKLOAD_TYPE_LOCK_MIXED_READ
+	)
+
+	tryLockCtx, tryLockCtxCancel := GetTimeoutCtx(time.Duration(l.config.MaxWaitTime))
+	defer tryLockCtxCancel()
+	if err = mutex.TryLock(tryLockCtx); err == nil {
+		acquireLatency = time.Since(start)
+		success = true
+
+		// Perform KV operation
+		newVal, _ := l.values.Generate(rg)
+
+		kvCtx, kvCtxCancel := GetTimeoutCtx(time.Duration(l.config.MaxWaitTime))
+		defer kvCtxCancel()
+		kvStart := time.Now()
+		if isRead {
+			_, err = client.Get(kvCtx, key)
+		} else {
+			_, err = client.Put(kvCtx, key, string(newVal))
+		}
+		kvLatency = time.Since(kvStart)
+		if err != nil {
+			statusCode, statusText = GetErrInfo(err)
+			success = false
+		}
+
+		unLockCtx, unLockCtxCancel := GetTimeoutCtx(time.Duration(l.config.MaxWaitTime))
+		defer unLockCtxCancel()
+		releaseStart := time.Now()
+		err = mutex.Unlock(unLockCtx)
+		releaseLatency = time.Since(releaseStart)
+		if err != nil {
+			success = false
+			l.logger.Printf("Failed to release the lock: %v", err)
+			lockOpStatusCode, lockOpStatusText = GetErrInfo(err)
+		}
+	} else if err == concurrency.ErrLocked {
+		l.logger.Printf("Failed to acquire the lock, lock is held by other session, : %v", err)
+	} else if err == concurrency.ErrSessionExpired {
+		l.logger.Printf("Failed to acquire the lock, session expired: %v", err)
+	}
+
+	if err != nil && lockOpStatusCode == 0 {
+		lockOpStatusCode, lockOpStatusText = GetErrInfo(err)
+	}
+
+	latency := acquireLatency + kvLatency + releaseLatency
+	if acquireLatency == 0 {
+		// the lock was not acquired, the attempt took the whole time
+		latency = time.Since(start)
+	}
+
+	operationStr := "lock-w"
+	if isRead {
+		operationStr = "lock-r"
+	}
+	return &LockMetric{
+		RequestMetric: &RequestMetric{
+			Timestamp:  time.Now(),
+			Key:        key,
+			Operation:  operationStr,
+			Latency:    latency,
+			Success:    success,
+			StatusCode: statusCode,
+			StatusText: statusText,
+			Err:        err,
+		},
+		AquireLatency:    acquireLatency,
+		ReleaseLatency:   releaseLatency,
+		LockOpStatusCode: lockOpStatusCode,
+		LockOpStatusText: lockOpStatusText,
+	}
+}
@@ -0,0 +1,97 @@
+package runner
+
+import (
+	"context"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// mvccResult is the outcome of an operation on past revisions of the keys
+type mvccResult struct {
+	keysScanned   int64 // versions read
+	responseBytes int64
+	revision      int64 // oldest revision read, 0 for the latest one
+	revisionLag   int64 // revisions between the oldest revision read and the latest known one
+}
+
+// observeRevision keeps the latest revision seen in the responses of etcd,
+// the reads of past revisions are relative to it
+func (kv *KVWorkload) observeRevision(header *etcdserverpb.ResponseHeader) {
+	if header == nil {
+		return
+	}
+	for {
+		latest := kv.revision.Load()
+		if header.Revision <= latest || kv.revision.CompareAndSwap(latest, header.Revision) {
+			return
+		}
+	}
+}
+
+// getAtRevision reads a key at a revision up to the lag behind the latest
+// known revision, the lag is uniformly distributed. Revisions which were
+// compacted fail with rpctypes.ErrCompacted.
+func (kv *KVWorkload) getAtRevision(ctx context.Context, w *Worker, key string, maxLag int64) (mvccResult, error) {
+	var res mvccResult
+	opts := []clientv3.OpOption{}
+	if latest := kv.revision.Load(); latest > 0 {
+		res.revisionLag = min(w.Rand.Int63n(maxLag+1), latest-1)
+		res.revision = latest - res.revisionLag
+		opts = append(opts, clientv3.WithRev(res.revision))
+	}
+	resp, err := w.Client.Get(ctx, key, opts...)
+	if err != nil {
+		return res, err
+	}
+	kv.observeRevision(resp.Header)
+	res.keysScanned = int64(len(resp.Kvs))
+	res.responseBytes = int64((*etcdserverpb.RangeResponse)(resp).Size())
+	return res, nil
+}
+
+// putPrevKV updates a key and returns its previous version with the response
+func (kv *KVWorkload) putPrevKV(ctx context.Context, w *Worker, key string, value string) (mvccResult, error) {
+	var res mvccResult
+	resp, err := w.Client.Put(ctx, key, value, clientv3.WithPrevKV())
+	if err != nil {
+		return res, err
+	}
+	kv.observeRevision(resp.Header)
+	if resp.PrevKv != nil {
+		res.keysScanned = 1
+	}
+	res.responseBytes = int64((*etcdserverpb.PutResponse)(resp).Size())
+	return res, nil
+}
+
+// walkHistory reads up to depth versions of a key, starting at the latest
+// one and going back to the revision before the modification of each
+// version. The walk ends early at the creation of the key and fails with
+// rpctypes.ErrCompacted when it reaches a compacted revision.
+func (kv *KVWorkload) walkHistory(ctx context.Context, w *Worker, key string, depth int) (mvccResult, error) {
+	var res mvccResult
+	opts := []clientv3.OpOption{}
+	for res.keysScanned < int64(depth) {
+		resp, err := w.Client.Get(ctx, key, opts...)
+		if err != nil {
+			return res, err
+		}
+		kv.observeRevision(resp.Header)
+		res.responseBytes += int64((*etcdserverpb.RangeResponse)(resp).Size())
+		if len(resp.Kvs) == 0 {
+			break
+		}
+		res.keysScanned++
+		version := resp.Kvs[0]
+		if version.ModRevision == version.CreateRevision {
+			break
+		}
+		res.revision = version.ModRevision - 1
+		opts = []clientv3.OpOption{clientv3.WithRev(res.revision)}
+	}
+	if res.revision > 0 {
+		res.revisionLag = max(kv.revision.Load()-res.revision, 0)
+	}
+	return res, nil
+}
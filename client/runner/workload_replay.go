@@ -0,0 +1,239 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	lg "csb/client/logger"
+	benchCfg "csb/control/config"
+	"csb/control/constants"
+	generator "csb/data-generator"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+func init() {
+	Register(constants.SCENARIO_REPLAY, constants.WORKLOAD_TYPE_TRACE, NewReplayWorkload)
+}
+
+// replayLease is a lease shared by the keys of the trace with the same TTL
+type replayLease struct {
+	id      clientv3.LeaseID
+	granted time.Time
+}
+
+// ReplayWorkload issues the operations of a recorded trace at their
+// original timing, scaled by the replay speed. The trace starts over once
+// all of its operations were issued. Every benchmark client replays its
+// share of the trace, the workers take the operations in the order of the
+// trace and wait for the instant they are due, the latency is measured from
+// that instant, so that operations which are issued late because all
+// workers are busy show up in the results.
+type ReplayWorkload struct {
+	config    *BenchmarkRunConfig
+	generator *generator.Generator
+	values    *generator.ValueGenerator
+	logger    *lg.Logger
+
+	records []benchCfg.TraceRecord // share of this benchmark client
+	offsets []time.Duration        // due time of every record after the start of the trace
+	period  time.Duration          // time until the trace starts over
+	next    atomic.Int64           // number of records claimed by the workers
+
+	leases  map[int64]*replayLease // leases by TTL
+	leaseMu sync.Mutex
+}
+
+func NewReplayWorkload(env *WorkloadEnv) (Workload, error) {
+	return &ReplayWorkload{
+		config:    env.Config,
+		generator: env.Generator,
+		values:    env.Values,
+		logger:    env.Logger,
+		leases:    make(map[int64]*replayLease),
+	}, nil
+}
+
+func (rw *ReplayWorkload) Setup(ctx context.Context) error {
+	trace, err := benchCfg.ReadTraceCSV(constants.DEFAULT_TRACE_FILE)
+	if err != nil {
+		return err
+	}
+	// the offsets are relative to the start of the whole trace, so that the
+	// benchmark clients replay their shares in step with each other
+	first, last := trace[0].Timestamp, trace[len(trace)-1].Timestamp
+	scale := func(ns int64) time.Duration {
+		return time.Duration(float64(ns) / rw.config.ReplaySpeed)
+	}
+	for i, record := range trace {
+		if i%rw.config.NumBenchClients != rw.config.ClientIndex {
+			continue
+		}
+		rw.records = append(rw.records, record)
+		rw.offsets = append(rw.offsets, scale(record.Timestamp-first))
+	}
+	if len(rw.records) == 0 {
+		return errors.New("the trace has no operations for this benchmark client")
+	}
+	// the next round follows after the mean gap between the operations
+	span := last - first
+	rw.period = scale(span + span/int64(max(len(trace)-1, 1)))
+	if rw.period <= 0 {
+		rw.period = time.Second
+	}
+	rw.logger.Printf("Replaying %d of %d operations of the trace, the trace takes %v", len(rw.records), len(trace), rw.period)
+	return nil
+}
+
+func (rw *ReplayWorkload) Teardown() error {
+	return nil
+}
+
+func (rw *ReplayWorkload) MetricHeader() []string {
+	return (&KVMetric{RequestMetric: &RequestMetric{}}).ToCSVHeader()
+}
+
+// take waits until the next record of the trace is due and claims it, the
+// trace starts with the warm-up. A record is only claimed once it is due, so
+// that the records which are not due before the end of a step are replayed
+// in the next step. It returns false if the context is done before.
+func (rw *ReplayWorkload) take(ctx context.Context) (benchCfg.TraceRecord, time.Time, bool) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		n := rw.next.Load()
+		i, round := int(n)%len(rw.records), int(n)/len(rw.records)
+		due := rw.config.StartTime.Add(time.Duration(round)*rw.period + rw.offsets[i])
+		if wait := time.Until(due); wait > 0 {
+			timer.Reset(wait)
+			select {
+			case <-ctx.Done():
+				return benchCfg.TraceRecord{}, time.Time{}, false
+			case <-timer.C:
+			}
+			// another worker may have claimed the record in the meantime
+			continue
+		}
+		if rw.next.CompareAndSwap(n, n+1) {
+			return rw.records[i], due, true
+		}
+	}
+}
+
+func (rw *ReplayWorkload) Execute(ctx context.Context, w *Worker) Metric {
+	record, due, ok := rw.take(ctx)
+	if !ok {
+		// no record was claimed, the metric is dropped at the end of the step
+		return &KVMetric{RequestMetric: &RequestMetric{Err: ctx.Err()}}
+	}
+	metric := &KVMetric{RequestMetric: &RequestMetric{Operation: record.Op, Key: record.Key}}
+
+	// a claimed record is replayed even if the step ends meanwhile, it is
+	// counted in the step it was due in
+	timeoutCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Duration(rw.config.MaxWaitTime))
+	err := rw.replay(timeoutCtx, w, record, metric)
+	cancel()
+	// operations issued late because all workers were busy are measured
+	// from the instant they were due as well
+	metric.Timestamp = time.Now()
+	metric.Latency = time.Since(due)
+	metric.Success = err == nil
+	metric.Err = err
+	if err != nil {
+		metric.StatusCode, metric.StatusText = GetErrInfo(err)
+	}
+	return metric
+}
+
+// replay issues the operation of a trace record
+func (rw *ReplayWorkload) replay(ctx context.Context, w *Worker, record benchCfg.TraceRecord, metric *KVMetric) error {
+	var opts []clientv3.OpOption
+	if record.RangeEnd != "" {
+		opts = append(opts, clientv3.WithRange(record.RangeEnd))
+	}
+	switch record.Op {
+	case constants.OPERATION_GET, constants.OPERATION_RANGE:
+		var err error
+		metric.KeysScanned, metric.ResponseBytes, err = pagedRange(ctx, w.Client, record.Key, 0, opts)
+		return err
+	case constants.OPERATION_DELETE:
+		resp, err := w.Client.Delete(ctx, record.Key, opts...)
+		if err != nil {
+			return err
+		}
+		metric.KeysScanned = resp.Deleted
+		metric.ResponseBytes = int64((*etcdserverpb.DeleteRangeResponse)(resp).Size())
+		return nil
+	default:
+		value, err := rw.values.GenerateSize(record.ValueSize, w.Rand)
+		if err != nil {
+			return err
+		}
+		if record.Lease > 0 {
+			lease, err := rw.leaseID(ctx, w.Client, record.Lease)
+			if err != nil {
+				return err
+			}
+			opts = append(opts, clientv3.WithLease(lease))
+		}
+		resp, err := w.Client.Put(ctx, record.Key, string(value), opts...)
+		if err != nil {
+			return err
+		}
+		metric.RequestBytes = int64(len(record.Key) + len(value))
+		metric.ResponseBytes = int64((*etcdserverpb.PutResponse)(resp).Size())
+		return nil
+	}
+}
+
+// leaseID returns the lease for keys with the given TTL, the keys share a
+// lease until half of its TTL passed, so that a key expires between half
+// the TTL and the TTL after it was written
+func (rw *ReplayWorkload) leaseID(ctx context.Context, cli *clientv3.Client, ttl int64) (clientv3.LeaseID, error) {
+	rw.leaseMu.Lock()
+	defer rw.leaseMu.Unlock()
+	if lease, ok := rw.leases[ttl]; ok && time.Since(lease.granted) < time.Duration(ttl)*time.Second/2 {
+		return lease.id, nil
+	}
+	resp, err := cli.Grant(ctx, ttl)
+	if err != nil {
+		return 0, err
+	}
+	rw.leases[ttl] = &replayLease{id: resp.ID, granted: time.Now()}
+	return resp.ID, nil
+}
+
+// TraceData returns the keys of the trace with their values, which are
+// loaded before the replay. A key gets the value size of its first put in
+// the trace.
+func TraceData(records []benchCfg.TraceRecord, values *generator.ValueGenerator, rg *rand.Rand) (map[string][]byte, error) {
+	sizes := make(map[string]int)
+	for _, record := range records {
+		if size, ok := sizes[record.Key]; ok && size >= 0 {
+			continue
+		}
+		sizes[record.Key] = -1
+		if record.Op == constants.OPERATION_PUT {
+			sizes[record.Key] = record.ValueSize
+		}
+	}
+	data := make(map[string][]byte, len(sizes))
+	for _, record := range records {
+		size, ok := sizes[record.Key]
+		if !ok {
+			continue
+		}
+		value, err := values.GenerateSize(size, rg)
+		if err != nil {
+			return nil, err
+		}
+		data[record.Key] = value
+		delete(sizes, record.Key)
+	}
+	return data, nil
+}
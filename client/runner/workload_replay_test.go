@@ -0,0 +1,66 @@
+package runner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	benchCfg "csb/control/config"
+)
+
+func newTestReplay(start time.Time) *ReplayWorkload {
+	return &ReplayWorkload{
+		config: &BenchmarkRunConfig{StartTime: start},
+		records: []benchCfg.TraceRecord{
+			{Key: "/a"}, {Key: "/b"}, {Key: "/c"},
+		},
+		offsets: []time.Duration{0, 20 * time.Millisecond, 40 * time.Millisecond},
+		period:  60 * time.Millisecond,
+	}
+}
+
+func TestReplayTakeWaitsUntilDue(t *testing.T) {
+	start := time.Now().Add(10 * time.Millisecond)
+	rw := newTestReplay(start)
+
+	// Test that the records are taken in the order of the trace at their
+	// offsets and the trace starts over after the period
+	wantKeys := []string{"/a", "/b", "/c", "/a"}
+	wantDue := []time.Duration{0, 20 * time.Millisecond, 40 * time.Millisecond, 60 * time.Millisecond}
+	for i, key := range wantKeys {
+		record, due, ok := rw.take(context.Background())
+		if !ok {
+			t.Fatalf("take() %d returned no record", i)
+		}
+		if record.Key != key || !due.Equal(start.Add(wantDue[i])) {
+			t.Errorf("take() %d = %s due at +%v, want %s due at +%v", i, record.Key, due.Sub(start), key, wantDue[i])
+		}
+		if now := time.Now(); now.Before(due) {
+			t.Errorf("take() %d returned %v before the record was due", i, due.Sub(now))
+		}
+	}
+}
+
+func TestReplayTakeClaimsOnlyDueRecords(t *testing.T) {
+	rw := newTestReplay(time.Now().Add(time.Hour))
+
+	// Test that a record which is not due before the end of a step is left
+	// for the next step
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, _, ok := rw.take(ctx); ok {
+		t.Fatal("take() returned a record which is not due")
+	}
+	if n := rw.next.Load(); n != 0 {
+		t.Errorf("%d records claimed after the context was done, want 0", n)
+	}
+
+	// Test that records issued late keep the instant they were due, so that
+	// the time they waited counts in their latency
+	start := time.Now().Add(-time.Second)
+	rw = newTestReplay(start)
+	_, due, ok := rw.take(context.Background())
+	if !ok || !due.Equal(start) {
+		t.Errorf("take() = due at %v, %v, want due at the start of the trace", due, ok)
+	}
+}
@@ -0,0 +1,118 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// errTxnConflict is returned when a transaction still conflicts after the
+// configured number of retries
+var errTxnConflict = errors.New("transaction aborted after too many conflicts")
+
+// txn updates keys with an optimistic transaction: the keys are read, then
+// written by a transaction which only applies if the mod revision of none of
+// the keys changed in between, otherwise it is retried
+func (kv *KVWorkload) txn(ctx context.Context, w *Worker, numKeys int) Metric {
+	keys := kv.pickKeys(w, numKeys)
+	newVal, _ := kv.values.Generate(w.Rand)
+	// the retries of a transaction share its timeout
+	timeoutCtx, cancel := context.WithTimeout(ctx, time.Duration(kv.config.MaxWaitTime))
+	defer cancel()
+
+	gets := make([]clientv3.Op, len(keys))
+	puts := make([]clientv3.Op, len(keys))
+	for i, key := range keys {
+		gets[i] = clientv3.OpGet(key)
+		puts[i] = clientv3.OpPut(key, string(newVal))
+	}
+
+	var conflicts, retries int
+	resp, err := w.Client.Txn(timeoutCtx).Then(gets...).Commit()
+	for err == nil {
+		revs := modRevisions(resp)
+		cmps := make([]clientv3.Cmp, len(keys))
+		for i, key := range keys {
+			cmps[i] = clientv3.Compare(clientv3.ModRevision(key), "=", revs[i])
+		}
+		// a failed compare reads the keys again for the next attempt
+		resp, err = w.Client.Txn(timeoutCtx).If(cmps...).Then(puts...).Else(gets...).Commit()
+		if err != nil || resp.Succeeded {
+			break
+		}
+		conflicts++
+		if retries == kv.config.TxnMaxRetries {
+			err = errTxnConflict
+			break
+		}
+		retries++
+	}
+	latency := time.Since(w.Start)
+
+	// a transaction of a single key is a compare-and-swap
+	operation := "txn"
+	if numKeys == 1 {
+		operation = "cas"
+	}
+	metric := &KVMetric{
+		RequestMetric: &RequestMetric{
+			Timestamp: time.Now(),
+			Key:       strings.Join(keys, ";"),
+			Operation: operation,
+			Latency:   latency,
+			Success:   err == nil,
+			Err:       err,
+		},
+		TxnKeys:   len(keys),
+		Conflicts: conflicts,
+		Retries:   retries,
+	}
+	if err == nil {
+		for _, key := range keys {
+			metric.RequestBytes += int64(len(key) + len(newVal))
+		}
+	} else {
+		metric.StatusCode, metric.StatusText = GetErrInfo(err)
+	}
+	return metric
+}
+
+// pickKeys returns the distinct keys of a transaction following the key
+// distribution, conflicting transactions update the first live keys of the
+// key space, which are shared by all clients
+func (kv *KVWorkload) pickKeys(w *Worker, numKeys int) []string {
+	if w.Rand.Float64()*100 < float64(kv.config.TxnConflictPercent) {
+		return kv.keys.First(numKeys)
+	}
+	keys := make([]string, 0, numKeys)
+	for attempt := 0; len(keys) < numKeys; attempt++ {
+		numLiveKeys := kv.keys.Len()
+		index := w.Keys.Next(w.Rand, numLiveKeys)
+		if attempt >= 100*numKeys {
+			// the distribution keeps picking the same keys, e.g. a hotspot
+			// smaller than the transaction
+			index = w.Rand.Intn(numLiveKeys)
+		}
+		key := kv.keys.Key(index)
+		if !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// modRevisions returns the mod revisions of the keys read by the range
+// operations of a transaction, 0 for keys which do not exist
+func modRevisions(resp *clientv3.TxnResponse) []int64 {
+	revs := make([]int64, len(resp.Responses))
+	for i, r := range resp.Responses {
+		if kvs := r.GetResponseRange().GetKvs(); len(kvs) > 0 {
+			revs[i] = kvs[0].ModRevision
+		}
+	}
+	return revs
+}
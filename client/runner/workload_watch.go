@@ -0,0 +1,304 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	lg "csb/client/logger"
+	benchCfg "csb/control/config"
+	"csb/control/constants"
+	generator "csb/data-generator"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+)
+
+// errWatchClosed is returned when the channel of a watch is closed without
+// an error, e.g. because the etcd client was closed
+var errWatchClosed = errors.New("watch channel closed")
+
+// errNoWriteTime is returned for events whose value does not start with the
+// benchmark client and the time of the write
+var errNoWriteTime = errors.New("value does not hold the time of its write")
+
+// number of workers issuing the writes of the watched keys
+const watchWriterWorkers = 16
+
+func init() {
+	for _, workloadType := range []string{
+		constants.WORKLOAD_TYPE_WATCH_KEY,
+		constants.WORKLOAD_TYPE_WATCH_PREFIX,
+	} {
+		Register(constants.SCENARIO_WATCH, workloadType, NewWatchWorkload)
+	}
+}
+
+// watcher is the watch of a single worker, it is kept across steps
+type watcher struct {
+	target   string
+	ch       clientv3.WatchChan
+	cancel   context.CancelFunc
+	pending  []*clientv3.Event // events received but not yet recorded
+	recvTime time.Time         // receipt of the response holding the pending events
+}
+
+// WatchWorkload measures the delivery of watch events: every worker of a
+// step watches a key or a prefix, while the watched keys are updated at a
+// fixed rate in the background. An operation of a worker is the receipt of
+// a single event of a write of this benchmark client, its latency is the
+// time from the write to the receipt. The events of the writes of other
+// benchmark clients are only counted, as the clocks of different hosts are
+// not in sync.
+type WatchWorkload struct {
+	config    *BenchmarkRunConfig
+	generator *generator.Generator
+	values    *generator.ValueGenerator
+	logger    *lg.Logger
+
+	keys        []string // watched and written keys
+	writer      *clientv3.Client
+	stopWriters context.CancelFunc
+	writersDone chan struct{}
+	writeErrors atomic.Int64
+	events      atomic.Int64 // events received by the watchers, see TakeEvents
+
+	// Watcher of every worker, removed when a step has fewer workers
+	watchers map[int]*watcher
+	mu       sync.Mutex
+}
+
+func NewWatchWorkload(env *WorkloadEnv) (Workload, error) {
+	return &WatchWorkload{
+		config:    env.Config,
+		generator: env.Generator,
+		values:    env.Values,
+		logger:    env.Logger,
+		watchers:  make(map[int]*watcher),
+	}, nil
+}
+
+func (wl *WatchWorkload) Setup(ctx context.Context) error {
+	wl.keys = wl.config.Keys[:min(wl.config.WatchKeys, len(wl.config.Keys))]
+	if len(wl.keys) == 0 {
+		return errors.New("no keys to watch")
+	}
+
+	var err error
+	wl.writer, err = clientv3.New(clientv3.Config{
+		Endpoints:   wl.config.Endpoints,
+		DialTimeout: 5 * time.Second,
+		Logger:      zap.NewNop(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create etcd client for the writers: %w", err)
+	}
+
+	// the writes run until the teardown, independent of the steps
+	writerCtx, cancel := context.WithCancel(context.Background())
+	wl.stopWriters = cancel
+	wl.writersDone = make(chan struct{})
+	go func() {
+		defer close(wl.writersDone)
+		runWorkers(writerCtx, watchWriterWorkers, wl.config.WatchWriteRate, func(writerID int) requestFunc {
+			// the random streams of the writers follow the ones of the watchers
+			rg := wl.generator.NewRand(wl.config.Seed+wl.config.SeedOffset, benchCfg.MaxWorkers(&wl.config.BenchctlConfig)+writerID)
+			return func(start time.Time) {
+				wl.write(writerCtx, rg)
+			}
+		})
+	}()
+	return nil
+}
+
+// write updates a random watched key, the value starts with the index of
+// this benchmark client and the time of the write, which replace the start
+// of the generated value
+func (wl *WatchWorkload) write(ctx context.Context, rg *rand.Rand) {
+	key := wl.keys[rg.Intn(len(wl.keys))]
+	padding, _ := wl.values.Generate(rg)
+	timeoutCtx, cancel := context.WithTimeout(ctx, time.Duration(wl.config.MaxWaitTime))
+	defer cancel()
+	value := fmt.Sprintf("%d:%d:", wl.config.ClientIndex, time.Now().UnixNano())
+	value += string(padding[min(len(value), len(padding)):])
+	if _, err := wl.writer.Put(timeoutCtx, key, value); err != nil && ctx.Err() == nil {
+		wl.writeErrors.Add(1)
+	}
+}
+
+// PrepareStep cancels the watchers of the workers which are not part of the step
+func (wl *WatchWorkload) PrepareStep(numWorkers int) error {
+	wl.mu.Lock()
+	defer wl.mu.Unlock()
+	for id, wt := range wl.watchers {
+		if id >= numWorkers {
+			wt.cancel()
+			delete(wl.watchers, id)
+		}
+	}
+	return nil
+}
+
+func (wl *WatchWorkload) Teardown() error {
+	wl.stopWriters()
+	<-wl.writersDone
+	if n := wl.writeErrors.Load(); n > 0 {
+		wl.logger.Printf("%d writes of the watched keys failed", n)
+	}
+
+	wl.mu.Lock()
+	for id, wt := range wl.watchers {
+		wt.cancel()
+		delete(wl.watchers, id)
+	}
+	wl.mu.Unlock()
+	return wl.writer.Close()
+}
+
+func (wl *WatchWorkload) MetricHeader() []string {
+	return (&WatchMetric{RequestMetric: &RequestMetric{}}).ToCSVHeader()
+}
+
+// TakeEvents returns the number of events received by the watchers since
+// the last call, including the events of other benchmark clients
+func (wl *WatchWorkload) TakeEvents() int64 {
+	return wl.events.Swap(0)
+}
+
+func (wl *WatchWorkload) Execute(ctx context.Context, w *Worker) Metric {
+	wt, err := wl.getWatcher(w)
+	var ev *clientv3.Event
+	var writeTime time.Time
+	for err == nil && ev == nil {
+		if len(wt.pending) == 0 {
+			err = wl.receive(ctx, wt)
+			continue
+		}
+		next := wt.pending[0]
+		wt.pending = wt.pending[1:]
+		// events of other benchmark clients and events without the time of
+		// their write, e.g. deletes or writes of other programs, are not
+		// measured
+		if writer, t, perr := parseWrite(next.Kv.Value); perr == nil && writer == wl.config.ClientIndex {
+			ev = next
+			writeTime = t
+		}
+	}
+
+	metric := &WatchMetric{
+		RequestMetric: &RequestMetric{
+			Operation: "watch",
+		},
+	}
+	if wt != nil {
+		metric.WatchTarget = wt.target
+	}
+	if err == nil {
+		metric.Key = string(ev.Kv.Key)
+		metric.Revision = ev.Kv.ModRevision
+		metric.Latency = wt.recvTime.Sub(writeTime)
+	} else if ctx.Err() == nil {
+		// a failed watch, e.g. because its revision was compacted, is
+		// replaced by a new one starting at the current revision
+		metric.Latency = time.Since(w.Start)
+		wl.removeWatcher(w.ID)
+	}
+
+	metric.Timestamp = time.Now()
+	metric.Success = err == nil
+	metric.Err = err
+	if err != nil {
+		metric.StatusCode, metric.StatusText = GetErrInfo(err)
+	}
+	return metric
+}
+
+// receive waits for the next response of the watch of a worker and keeps
+// its events for the following operations
+func (wl *WatchWorkload) receive(ctx context.Context, wt *watcher) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case resp, ok := <-wt.ch:
+		if !ok {
+			return errWatchClosed
+		}
+		if err := resp.Err(); err != nil {
+			return err
+		}
+		wt.pending = resp.Events
+		wt.recvTime = time.Now()
+		wl.events.Add(int64(len(resp.Events)))
+		return nil
+	}
+}
+
+// getWatcher returns the watcher of a worker, the watch is created on its
+// first operation
+func (wl *WatchWorkload) getWatcher(w *Worker) (*watcher, error) {
+	wl.mu.Lock()
+	defer wl.mu.Unlock()
+	if wt, ok := wl.watchers[w.ID]; ok {
+		return wt, nil
+	}
+
+	// the workers of all benchmark clients spread over the watched keys
+	key := wl.keys[(wl.config.ClientIDOffset+w.ID)%len(wl.keys)]
+	wt := &watcher{target: key}
+	var opts []clientv3.OpOption
+	if wl.config.WorkloadType == constants.WORKLOAD_TYPE_WATCH_PREFIX {
+		level := wl.config.WatchPrefixLevel
+		switch level {
+		case "":
+			level = constants.RANGE_LEVEL_SHARD
+		case constants.RANGE_LEVEL_MIXED:
+			level = rangeLevels[w.Rand.Intn(len(rangeLevels))]
+		}
+		prefix, err := generator.KeyPrefix(key, level)
+		if err != nil {
+			return nil, err
+		}
+		wt.target = prefix
+		opts = append(opts, clientv3.WithPrefix())
+	}
+
+	watchCtx, cancel := context.WithCancel(clientv3.WithRequireLeader(context.Background()))
+	wt.ch = w.Client.Watch(watchCtx, wt.target, opts...)
+	wt.cancel = cancel
+	wl.watchers[w.ID] = wt
+	return wt, nil
+}
+
+func (wl *WatchWorkload) removeWatcher(id int) {
+	wl.mu.Lock()
+	defer wl.mu.Unlock()
+	if wt, ok := wl.watchers[id]; ok {
+		wt.cancel()
+		delete(wl.watchers, id)
+	}
+}
+
+// parseWrite returns the index of the benchmark client and the time of the
+// write stored at the start of a value
+func parseWrite(value []byte) (int, time.Time, error) {
+	index, rest, found := strings.Cut(string(value), ":")
+	if !found {
+		return 0, time.Time{}, errNoWriteTime
+	}
+	nanos, _, found := strings.Cut(rest, ":")
+	writer, err := strconv.Atoi(index)
+	if err != nil || !found {
+		return 0, time.Time{}, errNoWriteTime
+	}
+	unixNano, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return 0, time.Time{}, errNoWriteTime
+	}
+	return writer, time.Unix(0, unixNano), nil
+}
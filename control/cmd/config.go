@@ -32,7 +32,8 @@ var configSetCmd = &cobra.Command{
 			fmt.Println("Config not found, please run 'benchctl config init' first")
 			os.Exit(1)
 		}
-		parts := strings.Split(args[0], "=")
+		// values may contain "=", e.g. the parameters of the operation mix
+		parts := strings.SplitN(args[0], "=", 2)
 		if len(parts) != 2 {
 			return fmt.Errorf("invalid format. Use: field=value")
 		}
@@ -94,6 +95,10 @@ var configSetCmd = &cobra.Command{
 		case reflect.Slice:
 			if fieldVal.Type().Elem().Kind() == reflect.String {
 				values := strings.Split(value, ",")
+				// an empty value clears the slice
+				if strings.TrimSpace(value) == "" {
+					values = nil
+				}
 				slice := reflect.MakeSlice(fieldVal.Type(), len(values), len(values))
 				for i, v := range values {
 					slice.Index(i).SetString(strings.TrimSpace(v))
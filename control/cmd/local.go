@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	constants "csb/control/constants"
+	"csb/control/local"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	// localMembers is the number of members of the local etcd cluster
+	localMembers int
+	// localBasePort is the client port of the first member of the local cluster
+	localBasePort int
+	// localDataDir keeps the data of the local cluster after the run if set
+	localDataDir string
+)
+
+var LocalCmd = &cobra.Command{
+	Use:   "local [flags] [<client_addr> ...]",
+	Short: "Run benchmarks against a local etcd cluster",
+	Long:  "Start an etcd cluster with 1, 3 or 5 members within the control program and run the benchmark against it, the endpoints of the config are replaced by the ones of the local cluster for this run. Each <client_addr> is the ip address along with port of a benchmark client running on this machine, the benchmark client on the default port is used if none is given",
+	Run: func(cmd *cobra.Command, args []string) {
+		log.SetPrefix("[LOCAL] ")
+		if GConfig.ctlConfig == nil {
+			fmt.Println("Config not found, please run 'benchctl config init' first")
+			os.Exit(1)
+		}
+		if localMembers != 1 && localMembers != 3 && localMembers != 5 {
+			fmt.Println("The local cluster supports 1, 3 or 5 members")
+			os.Exit(1)
+		}
+		if len(args) == 0 {
+			args = []string{fmt.Sprintf("127.0.0.1:%d", constants.DEFAULT_GRPC_SERVER_PORT)}
+		}
+
+		log.Printf("Starting local etcd cluster with %d members", localMembers)
+		cluster, err := local.StartCluster(localMembers, localBasePort, localDataDir, time.Minute)
+		if err != nil {
+			log.Fatalf("Failed to start local etcd cluster: %v", err)
+		}
+		GConfig.ctlConfig.Endpoints = cluster.Endpoints()
+		log.Printf("Local etcd cluster is ready, endpoints: %v, data directory: %s", GConfig.ctlConfig.Endpoints, cluster.DataDir())
+
+		log.SetPrefix("[RUN] ")
+		runErr := runBenchmark(args)
+		log.SetPrefix("[LOCAL] ")
+		log.Println("Stopping local etcd cluster")
+		if err := cluster.Close(); err != nil {
+			log.Printf("Failed to remove the data of the local etcd cluster: %v", err)
+		}
+		if runErr != nil {
+			log.Fatalf("Error from the benchmark run: %v", runErr)
+		}
+		log.Println("Benchmark terminated")
+	},
+}
+
+func init() {
+	LocalCmd.Flags().IntVarP(&localMembers, "members", "m", 1, "Number of members of the local etcd cluster (1, 3 or 5)")
+	LocalCmd.Flags().IntVar(&localBasePort, "base-port", constants.DEFAULT_LOCAL_BASE_PORT, "Client port of the first member, the members use consecutive pairs of client and peer ports")
+	LocalCmd.Flags().StringVar(&localDataDir, "data-dir", "", "Directory for the data of the local etcd cluster, which is kept after the run (default: a temporary directory)")
+	LocalCmd.Flags().DurationVar(&startDelay, "start-delay", 5*time.Second, "Delay between all benchmark clients being ready and the synchronized start of the benchmark")
+	LocalCmd.Flags().StringVarP(&resultsDir, "out-dir", "o", constants.DEFAULT_RESULTS_DIR, "Local directory to download the result files of the benchmark clients to")
+}
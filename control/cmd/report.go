@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	pb "csb/api/benchmarkpb"
+	benchCfg "csb/control/config"
+)
+
+const summaryFile = "summary.json"
+
+// stepSummary is the machine-readable form of a step report
+type stepSummary struct {
+	Client        string          `json:"client"`
+	StepIndex     int             `json:"step_index"`
+	Phase         string          `json:"phase"`
+	NumClients    int             `json:"num_clients"`
+	TargetRate    int64           `json:"target_rate_ops"`
+	StartTime     time.Time       `json:"start_time"`
+	EndTime       time.Time       `json:"end_time"`
+	Operations    int64           `json:"operations"`
+	Errors        int64           `json:"errors"`
+	Throughput    float64         `json:"throughput_ops"`
+	Bytes         int64           `json:"bytes"`
+	Bandwidth     float64         `json:"bandwidth_mb_s"`
+	LatencyMean   float64         `json:"latency_mean_ms"`
+	LatencyStdDev float64         `json:"latency_stddev_ms"`
+	LatencyP50    float64         `json:"latency_p50_ms"`
+	LatencyP90    float64         `json:"latency_p90_ms"`
+	LatencyP99    float64         `json:"latency_p99_ms"`
+	LatencyP999   float64         `json:"latency_p999_ms"`
+	LatencyMax    float64         `json:"latency_max_ms"`
+	ErrorsByCode  map[int32]int64 `json:"errors_by_code"`
+	// Events per second received by every watcher of the watch scenario
+	EventsPerWatcher float64 `json:"events_per_watcher,omitempty"`
+	// Results of the step by operation
+	ByOperation []operationSummary `json:"by_operation,omitempty"`
+}
+
+// operationSummary is the machine-readable form of an operation report
+type operationSummary struct {
+	Operation   string  `json:"operation"`
+	Operations  int64   `json:"operations"`
+	Errors      int64   `json:"errors"`
+	Throughput  float64 `json:"throughput_ops"`
+	Bytes       int64   `json:"bytes"`
+	Bandwidth   float64 `json:"bandwidth_mb_s"`
+	LatencyMean float64 `json:"latency_mean_ms"`
+	LatencyP50  float64 `json:"latency_p50_ms"`
+	LatencyP90  float64 `json:"latency_p90_ms"`
+	LatencyP99  float64 `json:"latency_p99_ms"`
+	LatencyMax  float64 `json:"latency_max_ms"`
+}
+
+// capacitySummary is the machine-readable form of a capacity report
+type capacitySummary struct {
+	Client        string       `json:"client"`
+	Found         bool         `json:"found"`
+	Converged     bool         `json:"converged"`
+	SLAPercentile float64      `json:"sla_percentile"`
+	SLALatency    float64      `json:"sla_latency_ms"`
+	Latency       float64      `json:"latency_ms"`
+	Knee          *stepSummary `json:"knee,omitempty"`
+}
+
+// runSummary collects the step reports of all benchmark clients of a run
+type runSummary struct {
+	Config   *benchCfg.BenchctlConfig `json:"config"`
+	Steps    []stepSummary            `json:"steps"`
+	Capacity []capacitySummary        `json:"capacity,omitempty"`
+}
+
+func usToMs(us int64) float64 {
+	return float64(us) / 1000
+}
+
+// formatLoad describes the load of a step, the target rate in open-loop mode
+// and the number of clients otherwise
+func formatLoad(numClients int, targetRate int64) string {
+	if targetRate > 0 {
+		return fmt.Sprintf("%d ops/s target rate", targetRate)
+	}
+	return fmt.Sprintf("%d clients", numClients)
+}
+
+func newStepSummary(client string, report *pb.StepReport) stepSummary {
+	return stepSummary{
+		Client:           client,
+		StepIndex:        int(report.StepIndex),
+		Phase:            report.Phase,
+		NumClients:       int(report.NumClients),
+		TargetRate:       report.TargetRate,
+		StartTime:        time.Unix(0, report.StartUnixNano),
+		EndTime:          time.Unix(0, report.EndUnixNano),
+		Operations:       report.Operations,
+		Errors:           report.Errors,
+		Throughput:       report.Throughput,
+		Bytes:            report.Bytes,
+		Bandwidth:        report.Bandwidth,
+		LatencyMean:      usToMs(report.LatencyMeanUs),
+		LatencyStdDev:    usToMs(report.LatencyStddevUs),
+		LatencyP50:       usToMs(report.LatencyP50Us),
+		LatencyP90:       usToMs(report.LatencyP90Us),
+		LatencyP99:       usToMs(report.LatencyP99Us),
+		LatencyP999:      usToMs(report.LatencyP999Us),
+		LatencyMax:       usToMs(report.LatencyMaxUs),
+		ErrorsByCode:     report.ErrorCounts,
+		ByOperation:      newOperationSummaries(report.OperationReports),
+		EventsPerWatcher: report.EventsPerWatcher,
+	}
+}
+
+func newOperationSummaries(reports []*pb.OperationReport) []operationSummary {
+	summaries := make([]operationSummary, 0, len(reports))
+	for _, report := range reports {
+		summaries = append(summaries, operationSummary{
+			Operation:   report.Operation,
+			Operations:  report.Operations,
+			Errors:      report.Errors,
+			Throughput:  report.Throughput,
+			Bytes:       report.Bytes,
+			Bandwidth:   report.Bandwidth,
+			LatencyMean: usToMs(report.LatencyMeanUs),
+			LatencyP50:  usToMs(report.LatencyP50Us),
+			LatencyP90:  usToMs(report.LatencyP90Us),
+			LatencyP99:  usToMs(report.LatencyP99Us),
+			LatencyMax:  usToMs(report.LatencyMaxUs),
+		})
+	}
+	return summaries
+}
+
+func (s *runSummary) addStepReport(client string, report *pb.StepReport) {
+	s.Steps = append(s.Steps, newStepSummary(client, report))
+}
+
+func (s *runSummary) addCapacityReport(client string, report *pb.CapacityReport) {
+	capacity := capacitySummary{
+		Client:        client,
+		Found:         report.Found,
+		Converged:     report.Converged,
+		SLAPercentile: report.SlaPercentile,
+		SLALatency:    usToMs(report.SlaLatencyUs),
+		Latency:       usToMs(report.LatencyUs),
+	}
+	if report.Knee != nil {
+		knee := newStepSummary(client, report.Knee)
+		capacity.Knee = &knee
+	}
+	s.Capacity = append(s.Capacity, capacity)
+}
+
+// sortSteps orders the steps by step index and then by client
+func (s *runSummary) sortSteps() {
+	sort.SliceStable(s.Steps, func(i, j int) bool {
+		if s.Steps[i].StepIndex != s.Steps[j].StepIndex {
+			return s.Steps[i].StepIndex < s.Steps[j].StepIndex
+		}
+		return s.Steps[i].Client < s.Steps[j].Client
+	})
+}
+
+// renderTable prints the step results as a table
+func (s *runSummary) renderTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "CLIENT\tSTEP\tPHASE\tCLIENTS\tTARGET(ops/s)\tOPS\tERRORS\tOPS/S\tMB/S\tMEAN(ms)\tP50(ms)\tP90(ms)\tP99(ms)\tP99.9(ms)\tMAX(ms)\t")
+	for _, step := range s.Steps {
+		target := "-"
+		if step.TargetRate > 0 {
+			target = fmt.Sprint(step.TargetRate)
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%d\t%s\t%d\t%d\t%.1f\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\t\n",
+			step.Client,
+			step.StepIndex,
+			step.Phase,
+			step.NumClients,
+			target,
+			step.Operations,
+			step.Errors,
+			step.Throughput,
+			step.Bandwidth,
+			step.LatencyMean,
+			step.LatencyP50,
+			step.LatencyP90,
+			step.LatencyP99,
+			step.LatencyP999,
+			step.LatencyMax)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if err := s.renderOperationTable(w); err != nil {
+		return err
+	}
+	for _, c := range s.Capacity {
+		if c.Knee == nil {
+			fmt.Fprintf(w, "\n%s: no step met the SLA (P%g <= %.2fms)\n", c.Client, c.SLAPercentile*100, c.SLALatency)
+			continue
+		}
+		fmt.Fprintf(w, "\n%s: capacity %s, %.1f ops/s, P%g %.2fms (SLA %.2fms, converged: %v)\n",
+			c.Client, formatLoad(c.Knee.NumClients, c.Knee.TargetRate), c.Knee.Throughput, c.SLAPercentile*100, c.Latency, c.SLALatency, c.Converged)
+	}
+	return nil
+}
+
+// renderOperationTable prints the results of the steps by operation side by
+// side, it is left out if every step has a single kind of operation
+func (s *runSummary) renderOperationTable(w io.Writer) error {
+	mixed := false
+	for _, step := range s.Steps {
+		mixed = mixed || len(step.ByOperation) > 1
+	}
+	if !mixed {
+		return nil
+	}
+
+	fmt.Fprintln(w)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "CLIENT\tSTEP\tPHASE\tOPERATION\tOPS\tERRORS\tOPS/S\tMB/S\tMEAN(ms)\tP50(ms)\tP90(ms)\tP99(ms)\tMAX(ms)\t")
+	for _, step := range s.Steps {
+		for _, op := range step.ByOperation {
+			fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%d\t%d\t%.1f\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\t\n",
+				step.Client,
+				step.StepIndex,
+				step.Phase,
+				op.Operation,
+				op.Operations,
+				op.Errors,
+				op.Throughput,
+				op.Bandwidth,
+				op.LatencyMean,
+				op.LatencyP50,
+				op.LatencyP90,
+				op.LatencyP99,
+				op.LatencyMax)
+		}
+	}
+	return tw.Flush()
+}
+
+// writeJSON saves the summary in the given directory and returns its path
+func (s *runSummary) writeJSON(dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, summaryFile)
+	return path, os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"context"
+	pb "csb/api/benchmarkpb"
+	constants "csb/control/constants"
+	grpcclient "csb/control/grpc"
+	"log"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+)
+
+var ResultsCmd = &cobra.Command{
+	Use:   "results",
+	Short: "Manage benchmark results",
+	Long:  "Collect the result files produced by the benchmark clients",
+}
+
+var resultsPullCmd = &cobra.Command{
+	Use:   "pull [flags] <client_addr>",
+	Short: "Download the result files of a benchmark client",
+	Long:  "Download the metrics file, the run log and the generated keys of a benchmark client into a local directory, or only the files given with --file. The <client_addr> is the ip address along with port of the benchmark client, which has to be still running",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		log.SetPrefix("[RESULTS] ")
+		conn, err := grpc.NewClient(args[0], clientDialOptions()...)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		outDir, _ := cmd.Flags().GetString("out-dir")
+		files, _ := cmd.Flags().GetStringSlice("file")
+		paths, err := grpcclient.PullResults(context.Background(), pb.NewBenchmarkServiceClient(conn), outDir, files)
+		for _, p := range paths {
+			log.Printf("Downloaded %s", p)
+		}
+		return err
+	},
+}
+
+func init() {
+	resultsPullCmd.Flags().StringP("out-dir", "o", constants.DEFAULT_RESULTS_DIR, "Local directory to download the result files to")
+	resultsPullCmd.Flags().StringSliceP("file", "f", nil, "Result file to download, e.g. run.log, can be repeated (default all result files)")
+	ResultsCmd.AddCommand(resultsPullCmd)
+}
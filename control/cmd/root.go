@@ -52,6 +52,9 @@ func init() {
 	loadConfig()
 	rootCmd.AddCommand(RunCmd)
 	rootCmd.AddCommand(ConfigCmd)
+	rootCmd.AddCommand(ResultsCmd)
+	rootCmd.AddCommand(LocalCmd)
+	rootCmd.AddCommand(TraceCmd)
 }
 
 func initConfigPath() {
@@ -3,12 +3,17 @@ package cmd
 import (
 	"context"
 	pb "csb/api/benchmarkpb"
+	benchCfg "csb/control/config"
+	constants "csb/control/constants"
 	grpcclient "csb/control/grpc"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
@@ -19,18 +24,26 @@ import (
 	"google.golang.org/grpc/keepalive"
 )
 
+var (
+	// startDelay is the time between all benchmark clients reporting
+	// readiness and the synchronized start of the benchmark
+	startDelay time.Duration
+	// resultsDir is the local directory the result files are downloaded to
+	resultsDir string
+)
+
 var RunCmd = &cobra.Command{
-	Use:   "run [flags] <client_addr>",
+	Use:   "run [flags] <client_addr> [<client_addr> ...]",
 	Short: "Run benchmarks",
-	Long:  "Run benchmarks against the database, sends control message to benchmark client to start the benchmark. The <client_addr> is the ip address along with port of the benchmark client",
-	Args:  cobra.ExactArgs(1),
+	Long:  "Run benchmarks against the database, sends control message to the benchmark clients to start the benchmark. Each <client_addr> is the ip address along with port of a benchmark client, all benchmark clients receive the same configuration and run the benchmark concurrently",
+	Args:  cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		log.SetPrefix("[RUN] ")
 		if GConfig.ctlConfig == nil {
 			fmt.Println("Config not found, please run 'benchctl config init' first")
 			os.Exit(1)
 		}
-		err := runBenchmark(args[0])
+		err := runBenchmark(args)
 		if err != nil {
 			log.Fatalf("Error from the benchmark run: %v", err)
 		}
@@ -38,105 +51,348 @@ var RunCmd = &cobra.Command{
 	},
 }
 
-func runBenchmark(clientAddr string) error {
-	termChan := make(chan struct{})
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+func init() {
+	RunCmd.Flags().DurationVar(&startDelay, "start-delay", 5*time.Second, "Delay between all benchmark clients being ready and the synchronized start of the benchmark")
+	RunCmd.Flags().StringVarP(&resultsDir, "out-dir", "o", constants.DEFAULT_RESULTS_DIR, "Local directory to download the result files of the benchmark clients to")
+}
 
-	dialOpts := []grpc.DialOption{
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithConnectParams(grpc.ConnectParams{
-			MinConnectTimeout: 30 * time.Second,
-		}),
-		grpc.WithKeepaliveParams(keepalive.ClientParameters{
-			Time:                30 * time.Second, // send pings every 30 seconds if there is no activity
-			Timeout:             60 * time.Second, // wait 60 seconds for ping responses
-			PermitWithoutStream: true,             // allow pings even without active streams
-		}),
+// benchClient holds the connection to one of the benchmark clients of a run
+type benchClient struct {
+	addr       string
+	conn       *grpc.ClientConn
+	service    *grpcclient.BenchmarkServiceClient
+	configured bool
+	prepared   bool
+	finished   bool
+}
+
+// clientMessage is a message, or the error, received from a benchmark client
+type clientMessage struct {
+	client *benchClient
+	msg    *pb.CTRLMessage
+	err    error
+}
+
+func runBenchmark(clientAddrs []string) error {
+	// the search adapts the load to the latencies measured by a single
+	// benchmark client, several clients would pick their steps independently
+	if GConfig.ctlConfig.LoadMode == constants.LOAD_MODE_SLA_SEARCH && len(clientAddrs) > 1 {
+		return fmt.Errorf("load mode %s supports a single benchmark client only", constants.LOAD_MODE_SLA_SEARCH)
 	}
 
-	// Connect to each client and send keys
-	conn, err := grpc.NewClient(clientAddr, dialOpts...)
+	cfg, err := clientConfig()
 	if err != nil {
 		return err
 	}
-
-	defer conn.Close()
-
-	ctx := context.Background()
-	benchmarkServiceClient, err := grpcclient.NewBenchmarkServiceClient(conn, ctx)
+	configData, err := json.Marshal(cfg)
 	if err != nil {
 		return err
 	}
+	// the client IDs of a benchmark client lie below the offset of the next
+	// one, even if a step runs more workers than the maximum number of clients
+	maxWorkers := benchCfg.MaxWorkers(cfg)
 
-	stream := benchmarkServiceClient.GetStream()
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
-	// wait for connection to be ready
-	log.Println("Waiting for connection to be ready")
-	for {
-		if conn.GetState() != connectivity.Ready {
+	dialOpts := clientDialOptions()
+	ctx := context.Background()
+	clients := make([]*benchClient, 0, len(clientAddrs))
+	defer func() {
+		for _, c := range clients {
+			c.conn.Close()
+		}
+	}()
+
+	// Connect to each client
+	for _, addr := range clientAddrs {
+		conn, err := grpc.NewClient(addr, dialOpts...)
+		if err != nil {
+			return err
+		}
+		client := &benchClient{addr: addr, conn: conn}
+		clients = append(clients, client)
+
+		client.service, err = grpcclient.NewBenchmarkServiceClient(conn, ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	// wait for all connections to be ready
+	for _, c := range clients {
+		log.Printf("[%s] Waiting for connection to be ready", c.addr)
+		for c.conn.GetState() != connectivity.Ready {
 			time.Sleep(100 * time.Millisecond)
-		} else {
-			log.Println("Connection is ready")
-			break
 		}
+		log.Printf("[%s] Connection is ready", c.addr)
+	}
+
+	// multiplex the streams of all clients into a single channel
+	msgChan := make(chan clientMessage)
+	doneChan := make(chan struct{})
+	defer close(doneChan)
+	for _, c := range clients {
+		go receiveMessages(c, msgChan, doneChan)
 	}
 
-	// send config file to the client
-	go func() {
-		if err := benchmarkServiceClient.SendConfigFile(ctx, GConfig.GetConfigFilePath()); err != nil {
-			log.Printf("Failed to send config file: %v", err)
-			terminate(stream, termChan)
+	// send the same config file to all clients, the offsets keep the random
+	// streams and client IDs of different benchmark clients apart. The trace
+	// of the replay scenario precedes the config file.
+	for i, c := range clients {
+		if GConfig.ctlConfig.Scenario == constants.SCENARIO_REPLAY {
+			if err := c.service.SendTrace(GConfig.ctlConfig.ReplayTraceFile, constants.DEFAULT_TRACE_FILE); err != nil {
+				log.Printf("[%s] Failed to send trace file: %v", c.addr, err)
+				terminate(clients)
+				return err
+			}
 		}
-	}()
+		assignment := grpcclient.ClientAssignment{
+			Index:          i,
+			NumClients:     len(clients),
+			SeedOffset:     int64(i) << 32,
+			ClientIDOffset: i * maxWorkers,
+		}
+		if err := c.service.SendConfig(ctx, configData, assignment); err != nil {
+			log.Printf("[%s] Failed to send config file: %v", c.addr, err)
+			terminate(clients)
+			return err
+		}
+	}
 
+	summary := &runSummary{Config: GConfig.ctlConfig}
+	numConfigured, numPrepared, numFinished := 0, 0, 0
 	for {
-		var err error
 		select {
 		case <-sigChan:
-			terminate(stream, termChan)
-		case <-termChan:
-			return err
-		default:
-			res, err := stream.Recv()
-
-			if err == io.EOF {
+			terminate(clients)
+			return nil
+		case m := <-msgChan:
+			if m.err == io.EOF {
 				// End of stream
-				log.Println("GRPC stream closed by server")
-				close(termChan)
-				return nil
+				log.Printf("[%s] GRPC stream closed by server", m.client.addr)
+				if m.client.finished {
+					continue
+				}
+				terminate(clients)
+				return fmt.Errorf("benchmark client %s closed the stream before the benchmark finished", m.client.addr)
 			}
 
-			if err != nil {
-				log.Printf("Error receiving from server: %v", err)
-				close(termChan)
-				return err
+			if m.err != nil {
+				log.Printf("[%s] Error receiving from server: %v", m.client.addr, m.err)
+				terminate(clients)
+				return m.err
 			}
 
 			// Handle server responses
-			switch payload := res.Payload.(type) {
+			switch payload := m.msg.Payload.(type) {
 			case *pb.CTRLMessage_BenchmarkStatus:
-				log.Printf("Benchmark status: %v", payload.BenchmarkStatus.Status)
+				log.Printf("[%s] Benchmark status: %v", m.client.addr, payload.BenchmarkStatus.Status)
+			case *pb.CTRLMessage_StepReport:
+				report := payload.StepReport
+				log.Printf("[%s] Step %d (%s) completed with %s, %.1f ops/s, %.2f MB/s, P99: %.2fms, #Ops: %d, #Errors: %d",
+					m.client.addr, report.StepIndex, report.Phase, formatLoad(int(report.NumClients), report.TargetRate), report.Throughput,
+					report.Bandwidth, usToMs(report.LatencyP99Us), report.Operations, report.Errors)
+				if report.EventsPerWatcher > 0 {
+					log.Printf("[%s] Step %d (%s) delivered %.1f events/s per watcher", m.client.addr, report.StepIndex, report.Phase, report.EventsPerWatcher)
+				}
+				summary.addStepReport(m.client.addr, report)
+			case *pb.CTRLMessage_CapacityReport:
+				report := payload.CapacityReport
+				if report.Found {
+					log.Printf("[%s] Capacity search finished (converged: %v), knee at %s, %.1f ops/s, P%g: %.2fms",
+						m.client.addr, report.Converged, formatLoad(int(report.Knee.NumClients), report.Knee.TargetRate), report.Knee.Throughput,
+						report.SlaPercentile*100, usToMs(report.LatencyUs))
+				} else {
+					log.Printf("[%s] Capacity search finished, no step met the SLA", m.client.addr)
+				}
+				summary.addCapacityReport(m.client.addr, report)
 			case *pb.CTRLMessage_ConfigFileResponse:
 				configReceived := payload.ConfigFileResponse.Success
-				log.Printf("Config file sent: %v", configReceived)
+				log.Printf("[%s] Config file sent: %v", m.client.addr, configReceived)
+				if !configReceived {
+					terminate(clients)
+					return fmt.Errorf("benchmark client %s rejected the config file", m.client.addr)
+				}
+				if !m.client.configured {
+					m.client.configured = true
+					numConfigured++
+				}
+				// data is only loaded once every client accepted the config
+				if numConfigured == len(clients) {
+					log.Println("All benchmark clients are configured, preparing the data")
+					if err := broadcast(clients, &pb.CTRLMessage{Payload: &pb.CTRLMessage_Prepare{Prepare: &pb.Prepare{}}}); err != nil {
+						terminate(clients)
+						return err
+					}
+				}
+			case *pb.CTRLMessage_PrepareDone:
+				log.Printf("[%s] Data prepared, number of keys: %d", m.client.addr, payload.PrepareDone.NumKeys)
+				if !m.client.prepared {
+					m.client.prepared = true
+					numPrepared++
+				}
+				// release all clients at the same wall-clock instant
+				if numPrepared == len(clients) {
+					startTime := time.Now().Add(startDelay)
+					log.Printf("All benchmark clients are ready, starting the benchmark at %v", startTime.Format(time.RFC3339Nano))
+					msg := &pb.CTRLMessage{
+						Payload: &pb.CTRLMessage_StartAt{
+							StartAt: &pb.StartAt{
+								UnixNano: startTime.UnixNano(),
+							},
+						},
+					}
+					if err := broadcast(clients, msg); err != nil {
+						terminate(clients)
+						return err
+					}
+				}
 			case *pb.CTRLMessage_BenchmarkFinished:
-				log.Println("Benchmark run finished")
-				terminate(stream, termChan)
+				log.Printf("[%s] Benchmark run finished", m.client.addr)
+				if !m.client.finished {
+					m.client.finished = true
+					numFinished++
+				}
+				if numFinished == len(clients) {
+					log.Println("Benchmark run finished on all clients")
+					err := pullResults(ctx, clients)
+					terminate(clients)
+					if summaryErr := reportSummary(summary); err == nil {
+						err = summaryErr
+					}
+					return err
+				}
 			default:
-				log.Printf("Unknown message type from server")
+				log.Printf("[%s] Unknown message type from server", m.client.addr)
 			}
 		}
 	}
 }
 
-func terminate(stream pb.BenchmarkService_CTRLStreamClient, termChan chan struct{}) {
-	log.Println("Terminating benchmark")
-	err := stream.Send(&pb.CTRLMessage{
-		Payload: &pb.CTRLMessage_Shutdown{},
-	})
+// clientConfig returns the config sent to the benchmark clients, the steps
+// of a CSV load profile and the value size histogram are read here because
+// the files only exist on this machine
+func clientConfig() (*benchCfg.BenchctlConfig, error) {
+	cfg := *GConfig.ctlConfig
+	if cfg.LoadMode == constants.LOAD_MODE_CSV {
+		steps, err := benchCfg.ReadLoadProfileCSV(cfg.LoadProfileFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read load profile: %w", err)
+		}
+		cfg.LoadSchedule = steps
+	}
+	if cfg.ValueSizeDistribution == constants.VALUE_SIZE_EMPIRICAL && cfg.ValueSizeFile != "" {
+		buckets, err := benchCfg.ReadValueSizeHistogramCSV(cfg.ValueSizeFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read value size histogram: %w", err)
+		}
+		cfg.ValueSizeHistogram = buckets
+	}
+	if cfg.Scenario == constants.SCENARIO_REPLAY {
+		// the trace is checked before it is sent to the benchmark clients
+		if _, err := benchCfg.ReadTraceCSV(cfg.ReplayTraceFile); err != nil {
+			return nil, fmt.Errorf("failed to read trace: %w", err)
+		}
+	}
+	return &cfg, nil
+}
+
+func clientDialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithConnectParams(grpc.ConnectParams{
+			MinConnectTimeout: 30 * time.Second,
+		}),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                30 * time.Second, // send pings every 30 seconds if there is no activity
+			Timeout:             60 * time.Second, // wait 60 seconds for ping responses
+			PermitWithoutStream: true,             // allow pings even without active streams
+		}),
+	}
+}
+
+// clientResultsDir returns the local directory for the result files of a
+// benchmark client, every client gets its own directory when there are several
+func clientResultsDir(baseDir string, clientAddr string, numClients int) string {
+	if numClients == 1 {
+		return baseDir
+	}
+	return filepath.Join(baseDir, strings.ReplaceAll(clientAddr, ":", "_"))
+}
+
+// pullResults downloads the result files of all benchmark clients before
+// they are shut down
+func pullResults(ctx context.Context, clients []*benchClient) error {
+	for _, c := range clients {
+		dir := clientResultsDir(resultsDir, c.addr, len(clients))
+		log.Printf("[%s] Downloading result files to %s", c.addr, dir)
+		paths, err := c.service.PullResults(ctx, dir, nil)
+		if err != nil {
+			log.Printf("[%s] Failed to download result files: %v", c.addr, err)
+			return err
+		}
+		for _, p := range paths {
+			log.Printf("[%s] Downloaded %s", c.addr, p)
+		}
+	}
+	return nil
+}
+
+// reportSummary prints the results of all steps and saves them in the
+// results directory
+func reportSummary(summary *runSummary) error {
+	summary.sortSteps()
+	fmt.Println()
+	if err := summary.renderTable(os.Stdout); err != nil {
+		return err
+	}
+	fmt.Println()
+	path, err := summary.writeJSON(resultsDir)
 	if err != nil {
-		log.Printf("Failed to send shutdown message: %v", err)
+		log.Printf("Failed to write the run summary: %v", err)
+		return err
+	}
+	log.Printf("Run summary saved in %s", path)
+	return nil
+}
+
+// receiveMessages forwards all messages received from a benchmark client
+// until its stream fails or is closed
+func receiveMessages(c *benchClient, msgChan chan<- clientMessage, doneChan <-chan struct{}) {
+	stream := c.service.GetStream()
+	for {
+		res, err := stream.Recv()
+		select {
+		case msgChan <- clientMessage{client: c, msg: res, err: err}:
+		case <-doneChan:
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// broadcast sends the same control message to all benchmark clients
+func broadcast(clients []*benchClient, msg *pb.CTRLMessage) error {
+	for _, c := range clients {
+		if err := c.service.GetStream().Send(msg); err != nil {
+			log.Printf("[%s] Failed to send control message: %v", c.addr, err)
+			return err
+		}
+	}
+	return nil
+}
+
+func terminate(clients []*benchClient) {
+	log.Println("Terminating benchmark")
+	for _, c := range clients {
+		err := c.service.GetStream().Send(&pb.CTRLMessage{
+			Payload: &pb.CTRLMessage_Shutdown{},
+		})
+		if err != nil {
+			log.Printf("[%s] Failed to send shutdown message: %v", c.addr, err)
+		}
 	}
-	close(termChan)
 }
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"cmp"
+	benchCfg "csb/control/config"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+)
+
+// traceOutput is the path of the trace written by the convert command
+var traceOutput string
+
+var TraceCmd = &cobra.Command{
+	Use:   "trace",
+	Short: "Manage the traces of the replay scenario",
+	Long:  "Create the traces which the replay scenario issues against the cluster",
+}
+
+var traceConvertCmd = &cobra.Command{
+	Use:   "convert [flags] <metrics_file> [<metrics_file> ...]",
+	Short: "Convert metrics files into a trace",
+	Long:  "Convert the metrics files of a past run into a trace for the replay scenario. The operations of several metrics files, e.g. of several benchmark clients, are merged by the instant they started. Operations which cannot be expressed in a trace, like watches and transactions, are skipped",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		log.SetPrefix("[TRACE] ")
+		records := make([]benchCfg.TraceRecord, 0)
+		for _, path := range args {
+			converted, skipped, err := convertMetricsFile(path)
+			if err != nil {
+				log.Fatalf("Failed to convert %s: %v", path, err)
+			}
+			log.Printf("Converted %d operations of %s, skipped %d operations", len(converted), path, skipped)
+			records = append(records, converted...)
+		}
+		if len(records) == 0 {
+			log.Fatalln("The metrics files contain no operations which can be replayed")
+		}
+		slices.SortStableFunc(records, func(a, b benchCfg.TraceRecord) int {
+			return cmp.Compare(a.Timestamp, b.Timestamp)
+		})
+
+		file, err := os.Create(traceOutput)
+		if err != nil {
+			log.Fatalf("Failed to create trace file: %v", err)
+		}
+		defer file.Close()
+		if err := benchCfg.WriteTrace(file, records); err != nil {
+			log.Fatalf("Failed to write trace file: %v", err)
+		}
+		log.Printf("Trace with %d operations written to %s", len(records), traceOutput)
+	},
+}
+
+func convertMetricsFile(path string) ([]benchCfg.TraceRecord, int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer file.Close()
+	records, skipped, err := benchCfg.ConvertMetrics(file)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid metrics file: %w", err)
+	}
+	return records, skipped, nil
+}
+
+func init() {
+	traceConvertCmd.Flags().StringVarP(&traceOutput, "output", "o", "trace.csv", "Path of the trace file")
+	TraceCmd.AddCommand(traceConvertCmd)
+}
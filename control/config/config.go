@@ -29,9 +29,122 @@ type BenchctlConfig struct {
 	MaxWaitTime    Duration `json:"max_wait_time" validate:"required"`
 	WorkloadType   string   `json:"workload_type" validate:"required,valid_workload_type"`
 	Scenario       string   `json:"scenario" validate:"required,valid_scenario"`
-	// SLA parameters
-	// SLALatency    Duration `json:"sla_latency"`
-	// SLAPercentile float64  `json:"sla_percentile"`
+	// Value sizes, an empty value size distribution is the same as "fixed".
+	// The value size is the mean of "normal" and the median of "lognormal"
+	// sizes, the minimum and maximum sizes bound the drawn sizes and are the
+	// range of "uniform" sizes. "empirical" draws the sizes of a histogram in
+	// the format "size:weight", which is filled from the value size file.
+	ValueSizeDistribution string   `json:"value_size_distribution" validate:"omitempty,valid_value_size_distribution"`
+	ValueSizeMin          int      `json:"value_size_min" validate:"gte=0"`
+	ValueSizeMax          int      `json:"value_size_max" validate:"gte=0"`
+	ValueSizeStdDev       float64  `json:"value_size_stddev" validate:"gte=0"`
+	ValueSizeSigma        float64  `json:"value_size_sigma" validate:"gte=0"`
+	ValueSizeHistogram    []string `json:"value_size_histogram" validate:"dive,valid_value_size_bucket"`
+	ValueSizeFile         string   `json:"value_size_file"`
+	// Content of the values, an empty value content is the same as "random"
+	ValueContent string `json:"value_content" validate:"omitempty,valid_value_content"`
+	// Load profile parameters, an empty load mode is the same as "ramp"
+	LoadMode string `json:"load_mode" validate:"omitempty,valid_load_mode"`
+	// Steps of the "steps" load mode in the format "level[@duration]", the
+	// "csv" load mode fills them from the load profile file
+	LoadSchedule    []string `json:"load_schedule" validate:"dive,valid_load_step"`
+	LoadProfileFile string   `json:"load_profile_file"`
+	SpikeInterval   Duration `json:"spike_interval" validate:"gte=0"`
+	SpikeDuration   Duration `json:"spike_duration" validate:"gte=0"`
+	SinePeriod      Duration `json:"sine_period" validate:"gte=0"`
+	// SLA parameters, required by the "sla-search" load mode. The latency
+	// percentile covers the successful operations, the share of failed
+	// operations must not exceed the maximum error rate.
+	SLALatency      Duration `json:"sla_latency" validate:"gte=0"`
+	SLAPercentile   float64  `json:"sla_percentile" validate:"gte=0,lt=1"`
+	SLAMaxErrorRate float64  `json:"sla_max_error_rate" validate:"gte=0,lte=1"`
+	// Loop mode, an empty loop mode is the same as "closed-loop"
+	LoopMode string `json:"loop_mode" validate:"omitempty,valid_loop_mode"`
+	// Open-loop parameters, the target rate in operations per second takes
+	// the place of the number of clients in the load profiles
+	InitialRate     int `json:"initial_rate" validate:"gte=0"`
+	RateStepSize    int `json:"rate_step_size" validate:"gte=0"`
+	MaxRate         int `json:"max_rate" validate:"gte=0"`
+	OpenLoopWorkers int `json:"open_loop_workers" validate:"gte=0"`
+	// Pacing of the clients in closed-loop mode, every client pauses for the
+	// think time after each operation and issues at most the client rate
+	// limit operations per second (0 for no limit), so that a client models
+	// an application instance rather than a busy loop. An empty think time
+	// distribution is the same as "fixed".
+	ThinkTime             Duration `json:"think_time" validate:"gte=0"`
+	ThinkTimeDistribution string   `json:"think_time_distribution" validate:"omitempty,valid_think_time_distribution"`
+	ClientRateLimit       float64  `json:"client_rate_limit" validate:"gte=0"`
+	// Key distribution, an empty key distribution is the same as "uniform".
+	// With "zipfian" and "latest" the popularity of the keys follows a
+	// zipfian distribution with the exponent theta, with "hotspot" the hot
+	// operations percent of the operations go to the hot keys percent of the
+	// keys.
+	KeyDistribution   string  `json:"key_distribution" validate:"omitempty,valid_key_distribution"`
+	ZipfianTheta      float64 `json:"zipfian_theta" validate:"gte=0,lt=1"`
+	HotspotKeyPercent int     `json:"hotspot_key_percent" validate:"gte=0,lte=100"`
+	HotspotOpPercent  int     `json:"hotspot_op_percent" validate:"gte=0,lte=100"`
+	// Read consistency of the reads of the kv-store scenario, with "mixed"
+	// the serializable percent of the reads are serializable
+	ReadConsistency     string `json:"read_consistency" validate:"omitempty,valid_read_consistency"`
+	SerializablePercent int    `json:"serializable_percent" validate:"gte=0,lte=100"`
+	// Operation mix of the kv-store scenario in the format
+	// "name:weight[:param=value...]", it replaces the preset mix of the
+	// workload type
+	OperationMix []string `json:"operation_mix" validate:"valid_operation_mix"`
+	// Range reads of the kv-store scenario, the share of all operations which
+	// scan the keys below a prefix of a random key, page by page if the
+	// limit is set, the other operations follow the workload type
+	RangePercent int    `json:"range_percent" validate:"gte=0,lte=100"`
+	RangeLevel   string `json:"range_level" validate:"omitempty,valid_range_level"`
+	RangeMode    string `json:"range_mode" validate:"omitempty,valid_range_mode"`
+	RangeLimit   int64  `json:"range_limit" validate:"gte=0"`
+	// Maximum number of keys read by a scan, the length of every scan is
+	// uniformly distributed between 1 and the maximum like in YCSB
+	ScanLength int `json:"scan_length" validate:"gte=0"`
+	// MVCC reads of the kv-store scenario, reads of past revisions go back
+	// up to the revision lag behind the latest known revision, history walks
+	// read up to the history depth versions of a key
+	RevisionLag  int64 `json:"revision_lag" validate:"gte=0"`
+	HistoryDepth int   `json:"history_depth" validate:"gte=0"`
+	// Large objects of the kv-store scenario, the values are uniformly
+	// distributed between the minimum size and the max request size of the
+	// etcd server (--max-request-bytes), which rejects larger requests
+	LargeValueMin   int `json:"large_value_min" validate:"gte=0"`
+	MaxRequestBytes int `json:"max_request_bytes" validate:"gte=0"`
+	// Transactions of the kv-store scenario, a share of the transactions
+	// update keys of a small set shared by all clients, which makes them
+	// conflict. Transactions are retried until they succeed or run out of
+	// retries. etcd allows 128 operations per transaction by default.
+	TxnKeys            int `json:"txn_keys" validate:"gte=0,lte=128"`
+	TxnConflictPercent int `json:"txn_conflict_percent" validate:"gte=0,lte=100"`
+	TxnMaxRetries      int `json:"txn_max_retries" validate:"gte=0"`
+	// Watch scenario, the clients of a step are watchers of the first keys,
+	// which are updated at a fixed rate by every benchmark client
+	WatchKeys        int    `json:"watch_keys" validate:"gte=0"`
+	WatchWriteRate   int    `json:"watch_write_rate" validate:"gte=0"`
+	WatchPrefixLevel string `json:"watch_prefix_level" validate:"omitempty,valid_range_level"`
+	// Lease scenario, the TTL of the leases in seconds and the number of
+	// leases every client keeps alive
+	LeaseTTL        int64 `json:"lease_ttl" validate:"gte=0"`
+	LeasesPerClient int   `json:"leases_per_client" validate:"gte=0"`
+	// Kubernetes scenario, the objects of every resource type in the format
+	// "resource:count" are spread over the namespaces. Lists read the
+	// objects in pages of the list limit, every benchmark client watches
+	// the prefix of every resource type with the given number of watches,
+	// events are attached to leases with the event TTL in seconds, and the
+	// revisions are compacted once per compaction interval like the
+	// kube-apiserver does (0 to disable).
+	K8sObjects            []string `json:"k8s_objects" validate:"dive,valid_object_count"`
+	K8sNamespaces         int      `json:"k8s_namespaces" validate:"gte=0"`
+	K8sListLimit          int64    `json:"k8s_list_limit" validate:"gte=0"`
+	K8sWatchesPerResource int      `json:"k8s_watches_per_resource" validate:"gte=0"`
+	K8sEventTTL           int64    `json:"k8s_event_ttl" validate:"gte=0"`
+	K8sCompactionInterval Duration `json:"k8s_compaction_interval" validate:"gte=0"`
+	// Replay scenario, the trace file is sent to the benchmark clients,
+	// which replay its operations at their original timing sped up by the
+	// replay speed
+	ReplayTraceFile string  `json:"replay_trace_file"`
+	ReplaySpeed     float64 `json:"replay_speed" validate:"gte=0"`
 	// Metrics parameters
 	MetricsFile string `json:"metrics_file" validate:"required,filepath"`
 }
@@ -42,6 +155,19 @@ const (
 	endpointTag     = "valid_endpoint"
 	keySizeTag      = "valid_key_size"
 	scenarioTag     = "valid_scenario"
+	loadModeTag     = "valid_load_mode"
+	loopModeTag     = "valid_loop_mode"
+	loadStepTag     = "valid_load_step"
+	rangeLevelTag   = "valid_range_level"
+	rangeModeTag    = "valid_range_mode"
+	keyDistTag      = "valid_key_distribution"
+	operationMixTag = "valid_operation_mix"
+	consistencyTag  = "valid_read_consistency"
+	valueSizeTag    = "valid_value_size_distribution"
+	valueBucketTag  = "valid_value_size_bucket"
+	valueContentTag = "valid_value_content"
+	objectCountTag  = "valid_object_count"
+	thinkTimeTag    = "valid_think_time_distribution"
 )
 
 // RegisterCustomValidators registers all custom validators for BenchctlConfig
@@ -66,8 +192,73 @@ func RegisterCustomValidators(v *validator.Validate) error {
 		return fmt.Errorf("failed to register key size validator: %w", err)
 	}
 
-	// Register scenario and workload type validator
-	v.RegisterStructValidation(validateScenarioAndWorkloadType, BenchctlConfig{})
+	// Register load mode validator
+	if err := v.RegisterValidation(loadModeTag, validateLoadMode); err != nil {
+		return fmt.Errorf("failed to register load mode validator: %w", err)
+	}
+
+	// Register loop mode validator
+	if err := v.RegisterValidation(loopModeTag, validateLoopMode); err != nil {
+		return fmt.Errorf("failed to register loop mode validator: %w", err)
+	}
+
+	// Register think time distribution validator
+	if err := v.RegisterValidation(thinkTimeTag, validateThinkTimeDistribution); err != nil {
+		return fmt.Errorf("failed to register think time distribution validator: %w", err)
+	}
+
+	// Register load step validator
+	if err := v.RegisterValidation(loadStepTag, validateLoadStep); err != nil {
+		return fmt.Errorf("failed to register load step validator: %w", err)
+	}
+
+	// Register range level validator
+	if err := v.RegisterValidation(rangeLevelTag, validateRangeLevel); err != nil {
+		return fmt.Errorf("failed to register range level validator: %w", err)
+	}
+
+	// Register range mode validator
+	if err := v.RegisterValidation(rangeModeTag, validateRangeMode); err != nil {
+		return fmt.Errorf("failed to register range mode validator: %w", err)
+	}
+
+	// Register key distribution validator
+	if err := v.RegisterValidation(keyDistTag, validateKeyDistribution); err != nil {
+		return fmt.Errorf("failed to register key distribution validator: %w", err)
+	}
+
+	// Register operation mix validator
+	if err := v.RegisterValidation(operationMixTag, validateOperationMixFormat); err != nil {
+		return fmt.Errorf("failed to register operation mix validator: %w", err)
+	}
+
+	// Register read consistency validator
+	if err := v.RegisterValidation(consistencyTag, validateReadConsistency); err != nil {
+		return fmt.Errorf("failed to register read consistency validator: %w", err)
+	}
+
+	// Register value size distribution validator
+	if err := v.RegisterValidation(valueSizeTag, validateValueSizeDistribution); err != nil {
+		return fmt.Errorf("failed to register value size distribution validator: %w", err)
+	}
+
+	// Register value size histogram bucket validator
+	if err := v.RegisterValidation(valueBucketTag, validateValueSizeBucket); err != nil {
+		return fmt.Errorf("failed to register value size bucket validator: %w", err)
+	}
+
+	// Register value content validator
+	if err := v.RegisterValidation(valueContentTag, validateValueContent); err != nil {
+		return fmt.Errorf("failed to register value content validator: %w", err)
+	}
+
+	// Register Kubernetes object count validator
+	if err := v.RegisterValidation(objectCountTag, validateObjectCount); err != nil {
+		return fmt.Errorf("failed to register object count validator: %w", err)
+	}
+
+	// Register validator for constraints spanning several fields
+	v.RegisterStructValidation(validateConfigStruct, BenchctlConfig{})
 
 	return nil
 }
@@ -81,13 +272,32 @@ func validateKeySize(fl validator.FieldLevel) bool {
 func validateWorkloadType(fl validator.FieldLevel) bool {
 	workloadType := fl.Field().String()
 	validTypes := map[string]bool{
-		constants.WORKLOAD_TYPE_READ_HEAVY:       true,
-		constants.WORKLOAD_TYPE_UPDATE_HEAVY:     true,
-		constants.WORKLOAD_TYPE_READ_ONLY:        true,
-		constants.WORKLOAD_TYPE_LOCK_ONLY:        true,
-		constants.WORKLOAD_TYPE_LOCK_MIXED_READ:  true,
-		constants.WORKLOAD_TYPE_LOCK_MIXED_WRITE: true,
-		constants.WORKLOAD_TYPE_LOCK_CONTENTION:  true,
+		constants.WORKLOAD_TYPE_READ_HEAVY:             true,
+		constants.WORKLOAD_TYPE_UPDATE_HEAVY:           true,
+		constants.WORKLOAD_TYPE_READ_ONLY:              true,
+		constants.WORKLOAD_TYPE_TXN_CAS:                true,
+		constants.WORKLOAD_TYPE_TXN_MULTI:              true,
+		constants.WORKLOAD_TYPE_READ_COMPARE:           true,
+		constants.WORKLOAD_TYPE_MVCC_HISTORY:           true,
+		constants.WORKLOAD_TYPE_LARGE_OBJECT:           true,
+		constants.WORKLOAD_TYPE_YCSB_A:                 true,
+		constants.WORKLOAD_TYPE_YCSB_B:                 true,
+		constants.WORKLOAD_TYPE_YCSB_C:                 true,
+		constants.WORKLOAD_TYPE_YCSB_D:                 true,
+		constants.WORKLOAD_TYPE_YCSB_E:                 true,
+		constants.WORKLOAD_TYPE_YCSB_F:                 true,
+		constants.WORKLOAD_TYPE_LOCK_ONLY:              true,
+		constants.WORKLOAD_TYPE_LOCK_MIXED_READ:        true,
+		constants.WORKLOAD_TYPE_LOCK_MIXED_WRITE:       true,
+		constants.WORKLOAD_TYPE_LOCK_CONTENTION:        true,
+		constants.WORKLOAD_TYPE_WATCH_KEY:              true,
+		constants.WORKLOAD_TYPE_WATCH_PREFIX:           true,
+		constants.WORKLOAD_TYPE_LEASE_GRANT_REVOKE:     true,
+		constants.WORKLOAD_TYPE_LEASE_KEEPALIVE:        true,
+		constants.WORKLOAD_TYPE_LEASE_KEEPALIVE_STREAM: true,
+		constants.WORKLOAD_TYPE_LEASE_EXPIRE:           true,
+		constants.WORKLOAD_TYPE_APISERVER:              true,
+		constants.WORKLOAD_TYPE_TRACE:                  true,
 	}
 
 	return validTypes[workloadType]
@@ -98,10 +308,142 @@ func validateScenarioType(fl validator.FieldLevel) bool {
 	validTypes := map[string]bool{
 		constants.SCENARIO_KV_STORE:     true,
 		constants.SCENARIO_LOCK_SERVICE: true,
+		constants.SCENARIO_WATCH:        true,
+		constants.SCENARIO_LEASE:        true,
+		constants.SCENARIO_KUBERNETES:   true,
+		constants.SCENARIO_REPLAY:       true,
 	}
 	return validTypes[scenarioType]
 }
 
+func validateLoadMode(fl validator.FieldLevel) bool {
+	loadMode := fl.Field().String()
+	validModes := map[string]bool{
+		constants.LOAD_MODE_RAMP:         true,
+		constants.LOAD_MODE_CONSTANT:     true,
+		constants.LOAD_MODE_RAMP_UP_DOWN: true,
+		constants.LOAD_MODE_STEPS:        true,
+		constants.LOAD_MODE_SPIKE:        true,
+		constants.LOAD_MODE_SINE:         true,
+		constants.LOAD_MODE_CSV:          true,
+		constants.LOAD_MODE_SLA_SEARCH:   true,
+	}
+	return validModes[loadMode]
+}
+
+func validateLoadStep(fl validator.FieldLevel) bool {
+	_, err := ParseLoadStep(fl.Field().String())
+	return err == nil
+}
+
+func validateOperationMixFormat(fl validator.FieldLevel) bool {
+	mix, ok := fl.Field().Interface().([]string)
+	if !ok {
+		return false
+	}
+	// an empty mix keeps the preset of the workload type
+	if len(mix) == 0 {
+		return true
+	}
+	_, err := ParseOperationMix(mix)
+	return err == nil
+}
+
+func validateReadConsistency(fl validator.FieldLevel) bool {
+	consistency := fl.Field().String()
+	validConsistencies := map[string]bool{
+		constants.READ_CONSISTENCY_LINEARIZABLE: true,
+		constants.READ_CONSISTENCY_SERIALIZABLE: true,
+		constants.READ_CONSISTENCY_MIXED:        true,
+	}
+	return validConsistencies[consistency]
+}
+
+func validateValueSizeDistribution(fl validator.FieldLevel) bool {
+	distribution := fl.Field().String()
+	validDistributions := map[string]bool{
+		constants.VALUE_SIZE_FIXED:     true,
+		constants.VALUE_SIZE_UNIFORM:   true,
+		constants.VALUE_SIZE_NORMAL:    true,
+		constants.VALUE_SIZE_LOGNORMAL: true,
+		constants.VALUE_SIZE_EMPIRICAL: true,
+	}
+	return validDistributions[distribution]
+}
+
+func validateValueSizeBucket(fl validator.FieldLevel) bool {
+	_, err := ParseValueSizeBucket(fl.Field().String())
+	return err == nil
+}
+
+func validateValueContent(fl validator.FieldLevel) bool {
+	content := fl.Field().String()
+	validContents := map[string]bool{
+		constants.VALUE_CONTENT_RANDOM: true,
+		constants.VALUE_CONTENT_TEXT:   true,
+		constants.VALUE_CONTENT_JSON:   true,
+	}
+	return validContents[content]
+}
+
+func validateObjectCount(fl validator.FieldLevel) bool {
+	_, err := ParseObjectCount(fl.Field().String())
+	return err == nil
+}
+
+func validateLoopMode(fl validator.FieldLevel) bool {
+	loopMode := fl.Field().String()
+	validModes := map[string]bool{
+		constants.LOOP_MODE_CLOSED: true,
+		constants.LOOP_MODE_OPEN:   true,
+	}
+	return validModes[loopMode]
+}
+
+func validateThinkTimeDistribution(fl validator.FieldLevel) bool {
+	distribution := fl.Field().String()
+	validDistributions := map[string]bool{
+		constants.THINK_TIME_FIXED:       true,
+		constants.THINK_TIME_EXPONENTIAL: true,
+		constants.THINK_TIME_UNIFORM:     true,
+	}
+	return validDistributions[distribution]
+}
+
+func validateRangeLevel(fl validator.FieldLevel) bool {
+	rangeLevel := fl.Field().String()
+	validLevels := map[string]bool{
+		constants.RANGE_LEVEL_DOMAIN: true,
+		constants.RANGE_LEVEL_REGION: true,
+		constants.RANGE_LEVEL_SHARD:  true,
+		constants.RANGE_LEVEL_MIXED:  true,
+	}
+	return validLevels[rangeLevel]
+}
+
+func validateRangeMode(fl validator.FieldLevel) bool {
+	rangeMode := fl.Field().String()
+	validModes := map[string]bool{
+		constants.RANGE_MODE_FULL:       true,
+		constants.RANGE_MODE_KEYS_ONLY:  true,
+		constants.RANGE_MODE_COUNT_ONLY: true,
+	}
+	return validModes[rangeMode]
+}
+
+func validateKeyDistribution(fl validator.FieldLevel) bool {
+	keyDistribution := fl.Field().String()
+	validDistributions := map[string]bool{
+		constants.KEY_DISTRIBUTION_UNIFORM:           true,
+		constants.KEY_DISTRIBUTION_ZIPFIAN:           true,
+		constants.KEY_DISTRIBUTION_SCRAMBLED_ZIPFIAN: true,
+		constants.KEY_DISTRIBUTION_HOTSPOT:           true,
+		constants.KEY_DISTRIBUTION_LATEST:            true,
+		constants.KEY_DISTRIBUTION_SEQUENTIAL:        true,
+	}
+	return validDistributions[keyDistribution]
+}
+
 // validateEndpoint ensures the endpoint string is in the correct format
 func validateEndpoint(fl validator.FieldLevel) bool {
 	endpoint := fl.Field().String()
@@ -131,15 +473,283 @@ func validateEndpoint(fl validator.FieldLevel) bool {
 
 }
 
-func validateScenarioAndWorkloadType(sl validator.StructLevel) {
+func validateConfigStruct(sl validator.StructLevel) {
 	cfg := sl.Current().Interface().(BenchctlConfig)
+	validateScenarioAndWorkloadType(sl, cfg)
+	validateSLA(sl, cfg)
+	validateLoadProfile(sl, cfg)
+	validateOpenLoop(sl, cfg)
+	validatePacing(sl, cfg)
+	validateTxn(sl, cfg)
+	validateOperationMix(sl, cfg)
+	validateWatch(sl, cfg)
+	validateLease(sl, cfg)
+	validateKeyDistributionParams(sl, cfg)
+	validateValueSizes(sl, cfg)
+	validateLargeObject(sl, cfg)
+	validateKubernetes(sl, cfg)
+	validateReplay(sl, cfg)
+}
+
+// validateReplay ensures the replay scenario has a trace and a speed, the
+// timing of the operations is given by the trace, so they cannot be driven
+// at a target rate
+func validateReplay(sl validator.StructLevel, cfg BenchctlConfig) {
+	if cfg.Scenario != constants.SCENARIO_REPLAY {
+		return
+	}
+	if cfg.ReplayTraceFile == "" {
+		sl.ReportError(cfg.ReplayTraceFile, "replay_trace_file", "ReplayTraceFile", "requiredForReplay", "")
+	}
+	if cfg.ReplaySpeed <= 0 {
+		sl.ReportError(cfg.ReplaySpeed, "replay_speed", "ReplaySpeed", "requiredForReplay", "")
+	}
+	if cfg.LoopMode == constants.LOOP_MODE_OPEN {
+		sl.ReportError(cfg.LoopMode, "loop_mode", "LoopMode", "closedLoopForReplay", "")
+	}
+}
+
+// validateKubernetes ensures the kubernetes scenario has objects in
+// namespaces and a TTL for its events
+func validateKubernetes(sl validator.StructLevel, cfg BenchctlConfig) {
+	if cfg.Scenario != constants.SCENARIO_KUBERNETES {
+		return
+	}
+	if _, err := ParseObjectCounts(cfg.K8sObjects); err != nil {
+		sl.ReportError(cfg.K8sObjects, "k8s_objects", "K8sObjects", "requiredForKubernetes", "")
+	}
+	if cfg.K8sNamespaces <= 0 {
+		sl.ReportError(cfg.K8sNamespaces, "k8s_namespaces", "K8sNamespaces", "requiredForKubernetes", "")
+	}
+	if cfg.K8sEventTTL <= 0 {
+		sl.ReportError(cfg.K8sEventTTL, "k8s_event_ttl", "K8sEventTTL", "requiredForKubernetes", "")
+	}
+}
+
+// validateLargeObject ensures the large-object workload type has a range of
+// value sizes
+func validateLargeObject(sl validator.StructLevel, cfg BenchctlConfig) {
+	if cfg.WorkloadType != constants.WORKLOAD_TYPE_LARGE_OBJECT {
+		return
+	}
+	if cfg.LargeValueMin <= 0 {
+		sl.ReportError(cfg.LargeValueMin, "large_value_min", "LargeValueMin", "requiredForLargeObject", "")
+	}
+	if cfg.MaxRequestBytes < cfg.LargeValueMin {
+		sl.ReportError(cfg.MaxRequestBytes, "max_request_bytes", "MaxRequestBytes", "gtefield", "LargeValueMin")
+	}
+}
+
+// validateValueSizes ensures the parameters of the value size distribution
+// are defined
+func validateValueSizes(sl validator.StructLevel, cfg BenchctlConfig) {
+	if cfg.ValueSizeMax > 0 && cfg.ValueSizeMax < cfg.ValueSizeMin {
+		sl.ReportError(cfg.ValueSizeMax, "value_size_max", "ValueSizeMax", "gtefield", "ValueSizeMin")
+	}
+	switch cfg.ValueSizeDistribution {
+	case constants.VALUE_SIZE_UNIFORM:
+		if cfg.ValueSizeMax <= 0 {
+			sl.ReportError(cfg.ValueSizeMax, "value_size_max", "ValueSizeMax", "requiredForUniform", "")
+		}
+	case constants.VALUE_SIZE_NORMAL:
+		if cfg.ValueSizeStdDev <= 0 {
+			sl.ReportError(cfg.ValueSizeStdDev, "value_size_stddev", "ValueSizeStdDev", "requiredForNormal", "")
+		}
+	case constants.VALUE_SIZE_LOGNORMAL:
+		if cfg.ValueSizeSigma <= 0 {
+			sl.ReportError(cfg.ValueSizeSigma, "value_size_sigma", "ValueSizeSigma", "requiredForLognormal", "")
+		}
+	case constants.VALUE_SIZE_EMPIRICAL:
+		if cfg.ValueSizeFile == "" && len(cfg.ValueSizeHistogram) == 0 {
+			sl.ReportError(cfg.ValueSizeFile, "value_size_file", "ValueSizeFile", "requiredForEmpirical", "")
+		} else if len(cfg.ValueSizeHistogram) > 0 {
+			if _, err := ParseValueSizeHistogram(cfg.ValueSizeHistogram); err != nil {
+				sl.ReportError(cfg.ValueSizeHistogram, "value_size_histogram", "ValueSizeHistogram", "positiveWeight", "")
+			}
+		}
+	}
+}
+
+// validateKeyDistributionParams ensures the parameters of the skewed key
+// distributions are defined
+func validateKeyDistributionParams(sl validator.StructLevel, cfg BenchctlConfig) {
+	switch cfg.KeyDistribution {
+	case constants.KEY_DISTRIBUTION_ZIPFIAN, constants.KEY_DISTRIBUTION_SCRAMBLED_ZIPFIAN, constants.KEY_DISTRIBUTION_LATEST:
+		if cfg.ZipfianTheta <= 0 {
+			sl.ReportError(cfg.ZipfianTheta, "zipfian_theta", "ZipfianTheta", "requiredForZipfian", "")
+		}
+	case constants.KEY_DISTRIBUTION_HOTSPOT:
+		if cfg.HotspotKeyPercent <= 0 || cfg.HotspotKeyPercent >= 100 {
+			sl.ReportError(cfg.HotspotKeyPercent, "hotspot_key_percent", "HotspotKeyPercent", "requiredForHotspot", "")
+		}
+	}
+}
+
+// validateTxn ensures the multi-key transactions touch at least one key, the
+// scans of YCSB workload E read at least one key and the history walks of
+// the mvcc-history workload type read at least one version
+func validateTxn(sl validator.StructLevel, cfg BenchctlConfig) {
+	if cfg.WorkloadType == constants.WORKLOAD_TYPE_TXN_MULTI && cfg.TxnKeys <= 0 {
+		sl.ReportError(cfg.TxnKeys, "txn_keys", "TxnKeys", "requiredForTxnMulti", "")
+	}
+	if cfg.WorkloadType == constants.WORKLOAD_TYPE_YCSB_E && cfg.ScanLength <= 0 {
+		sl.ReportError(cfg.ScanLength, "scan_length", "ScanLength", "requiredForYCSBE", "")
+	}
+	if cfg.WorkloadType == constants.WORKLOAD_TYPE_MVCC_HISTORY && cfg.HistoryDepth <= 0 {
+		sl.ReportError(cfg.HistoryDepth, "history_depth", "HistoryDepth", "requiredForMVCCHistory", "")
+	}
+}
 
+// validateOperationMix ensures the operation mix is only used by the kv-store
+// scenario and its transactions touch at least one key
+func validateOperationMix(sl validator.StructLevel, cfg BenchctlConfig) {
+	if len(cfg.OperationMix) == 0 {
+		return
+	}
+	if cfg.Scenario != constants.SCENARIO_KV_STORE {
+		sl.ReportError(cfg.OperationMix, "operation_mix", "OperationMix", "onlyForKVStore", "")
+		return
+	}
+	ops, err := ParseOperationMix(cfg.OperationMix)
+	if err != nil {
+		return
+	}
+	for _, op := range ops {
+		if op.Name == constants.OPERATION_TXN && op.Params["keys"] == "" && cfg.TxnKeys <= 0 {
+			sl.ReportError(cfg.TxnKeys, "txn_keys", "TxnKeys", "requiredForTxn", "")
+		}
+		if op.Name == constants.OPERATION_SCAN && op.Params["length"] == "" && cfg.ScanLength <= 0 {
+			sl.ReportError(cfg.ScanLength, "scan_length", "ScanLength", "requiredForScan", "")
+		}
+		if op.Name == constants.OPERATION_HISTORY && op.Params["depth"] == "" && cfg.HistoryDepth <= 0 {
+			sl.ReportError(cfg.HistoryDepth, "history_depth", "HistoryDepth", "requiredForHistory", "")
+		}
+	}
+}
+
+// validateLoadProfile ensures the parameters of the load profile are defined
+func validateLoadProfile(sl validator.StructLevel, cfg BenchctlConfig) {
+	switch cfg.LoadMode {
+	case constants.LOAD_MODE_STEPS:
+		if len(cfg.LoadSchedule) == 0 {
+			sl.ReportError(cfg.LoadSchedule, "load_schedule", "LoadSchedule", "requiredForSteps", "")
+		}
+	case constants.LOAD_MODE_CSV:
+		if cfg.LoadProfileFile == "" {
+			sl.ReportError(cfg.LoadProfileFile, "load_profile_file", "LoadProfileFile", "requiredForCSV", "")
+		}
+	case constants.LOAD_MODE_SPIKE:
+		if cfg.SpikeDuration <= 0 || cfg.SpikeDuration >= cfg.SpikeInterval {
+			sl.ReportError(cfg.SpikeDuration, "spike_duration", "SpikeDuration", "ltfield", "SpikeInterval")
+		}
+	case constants.LOAD_MODE_SINE:
+		if cfg.SinePeriod <= 0 {
+			sl.ReportError(cfg.SinePeriod, "sine_period", "SinePeriod", "requiredForSine", "")
+		}
+	}
+}
+
+// validateSLA ensures the SLA is defined when the load mode relies on it
+func validateSLA(sl validator.StructLevel, cfg BenchctlConfig) {
+	if cfg.LoadMode != constants.LOAD_MODE_SLA_SEARCH {
+		return
+	}
+	if cfg.SLALatency <= 0 {
+		sl.ReportError(cfg.SLALatency, "sla_latency", "SLALatency", "requiredForSLASearch", "")
+	}
+	if cfg.SLAPercentile <= 0 {
+		sl.ReportError(cfg.SLAPercentile, "sla_percentile", "SLAPercentile", "requiredForSLASearch", "")
+	}
+}
+
+// validateOpenLoop ensures the target rates are defined in open-loop mode,
+// the schedule based load modes define the rates in their steps instead
+func validateOpenLoop(sl validator.StructLevel, cfg BenchctlConfig) {
+	if cfg.LoopMode != constants.LOOP_MODE_OPEN {
+		return
+	}
+	if cfg.OpenLoopWorkers <= 0 {
+		sl.ReportError(cfg.OpenLoopWorkers, "open_loop_workers", "OpenLoopWorkers", "requiredForOpenLoop", "")
+	}
+	if cfg.LoadMode == constants.LOAD_MODE_STEPS || cfg.LoadMode == constants.LOAD_MODE_CSV {
+		return
+	}
+	if cfg.InitialRate <= 0 {
+		sl.ReportError(cfg.InitialRate, "initial_rate", "InitialRate", "requiredForOpenLoop", "")
+	}
+	if cfg.RateStepSize <= 0 {
+		sl.ReportError(cfg.RateStepSize, "rate_step_size", "RateStepSize", "requiredForOpenLoop", "")
+	}
+	if cfg.MaxRate < cfg.InitialRate {
+		sl.ReportError(cfg.MaxRate, "max_rate", "MaxRate", "gtefield", "InitialRate")
+	}
+}
+
+// validatePacing ensures the think time and the client rate limit are only
+// used in closed-loop mode, the requests of the open-loop mode are paced by
+// the target rate
+func validatePacing(sl validator.StructLevel, cfg BenchctlConfig) {
+	if cfg.LoopMode != constants.LOOP_MODE_OPEN {
+		return
+	}
+	if cfg.ThinkTime > 0 {
+		sl.ReportError(cfg.ThinkTime, "think_time", "ThinkTime", "closedLoopForThinkTime", "")
+	}
+	if cfg.ClientRateLimit > 0 {
+		sl.ReportError(cfg.ClientRateLimit, "client_rate_limit", "ClientRateLimit", "closedLoopForRateLimit", "")
+	}
+}
+
+// validateWatch ensures the watch scenario has keys to watch and write, the
+// watchers wait for events, so they cannot be driven at a target rate
+func validateWatch(sl validator.StructLevel, cfg BenchctlConfig) {
+	if cfg.Scenario != constants.SCENARIO_WATCH {
+		return
+	}
+	if cfg.WatchKeys <= 0 {
+		sl.ReportError(cfg.WatchKeys, "watch_keys", "WatchKeys", "requiredForWatch", "")
+	}
+	if cfg.WatchWriteRate <= 0 {
+		sl.ReportError(cfg.WatchWriteRate, "watch_write_rate", "WatchWriteRate", "requiredForWatch", "")
+	}
+	if cfg.LoopMode == constants.LOOP_MODE_OPEN {
+		sl.ReportError(cfg.LoopMode, "loop_mode", "LoopMode", "closedLoopForWatch", "")
+	}
+}
+
+// validateLease ensures the lease scenario has a TTL and leases to keep alive
+func validateLease(sl validator.StructLevel, cfg BenchctlConfig) {
+	if cfg.Scenario != constants.SCENARIO_LEASE {
+		return
+	}
+	if cfg.LeaseTTL <= 0 {
+		sl.ReportError(cfg.LeaseTTL, "lease_ttl", "LeaseTTL", "requiredForLease", "")
+	}
+	keepAlive := cfg.WorkloadType == constants.WORKLOAD_TYPE_LEASE_KEEPALIVE ||
+		cfg.WorkloadType == constants.WORKLOAD_TYPE_LEASE_KEEPALIVE_STREAM
+	if keepAlive && cfg.LeasesPerClient <= 0 {
+		sl.ReportError(cfg.LeasesPerClient, "leases_per_client", "LeasesPerClient", "requiredForKeepAlive", "")
+	}
+}
+
+func validateScenarioAndWorkloadType(sl validator.StructLevel, cfg BenchctlConfig) {
 	// Define valid workload types for each scenario
 	validWorkloads := map[string]map[string]bool{
 		constants.SCENARIO_KV_STORE: {
 			constants.WORKLOAD_TYPE_READ_HEAVY:   true,
 			constants.WORKLOAD_TYPE_UPDATE_HEAVY: true,
 			constants.WORKLOAD_TYPE_READ_ONLY:    true,
+			constants.WORKLOAD_TYPE_TXN_CAS:      true,
+			constants.WORKLOAD_TYPE_TXN_MULTI:    true,
+			constants.WORKLOAD_TYPE_READ_COMPARE: true,
+			constants.WORKLOAD_TYPE_MVCC_HISTORY: true,
+			constants.WORKLOAD_TYPE_LARGE_OBJECT: true,
+			constants.WORKLOAD_TYPE_YCSB_A:       true,
+			constants.WORKLOAD_TYPE_YCSB_B:       true,
+			constants.WORKLOAD_TYPE_YCSB_C:       true,
+			constants.WORKLOAD_TYPE_YCSB_D:       true,
+			constants.WORKLOAD_TYPE_YCSB_E:       true,
+			constants.WORKLOAD_TYPE_YCSB_F:       true,
 		},
 		constants.SCENARIO_LOCK_SERVICE: {
 			constants.WORKLOAD_TYPE_LOCK_ONLY:        true,
@@ -147,6 +757,22 @@ func validateScenarioAndWorkloadType(sl validator.StructLevel) {
 			constants.WORKLOAD_TYPE_LOCK_MIXED_WRITE: true,
 			constants.WORKLOAD_TYPE_LOCK_CONTENTION:  true,
 		},
+		constants.SCENARIO_WATCH: {
+			constants.WORKLOAD_TYPE_WATCH_KEY:    true,
+			constants.WORKLOAD_TYPE_WATCH_PREFIX: true,
+		},
+		constants.SCENARIO_LEASE: {
+			constants.WORKLOAD_TYPE_LEASE_GRANT_REVOKE:     true,
+			constants.WORKLOAD_TYPE_LEASE_KEEPALIVE:        true,
+			constants.WORKLOAD_TYPE_LEASE_KEEPALIVE_STREAM: true,
+			constants.WORKLOAD_TYPE_LEASE_EXPIRE:           true,
+		},
+		constants.SCENARIO_KUBERNETES: {
+			constants.WORKLOAD_TYPE_APISERVER: true,
+		},
+		constants.SCENARIO_REPLAY: {
+			constants.WORKLOAD_TYPE_TRACE: true,
+		},
 	}
 
 	// Check if scenario exists in the validWorkloads map
@@ -163,23 +789,73 @@ func validateScenarioAndWorkloadType(sl validator.StructLevel) {
 
 func GetDefaultConfig() *BenchctlConfig {
 	return &BenchctlConfig{
-		Seed:           constants.DEFAULT_SEED,
-		NumKeys:        constants.DEFAULT_NUM_KEYS,
-		Endpoints:      []string{},
-		KeySize:        16,
-		ValueSize:      128,
-		WarmupDuration: Duration(5 * time.Minute),
-		StepDuration:   Duration(1 * time.Minute),
-		TotalDuration:  Duration(30 * time.Minute),
-		InitialClients: 5,
-		ClientStepSize: 5,
-		MaxClients:     100,
-		MaxWaitTime:    Duration(500 * time.Millisecond),
-		WorkloadType:   constants.WORKLOAD_TYPE_READ_HEAVY,
-		Scenario:       constants.SCENARIO_KV_STORE,
-		// SLALatency:     Duration(100 * time.Millisecond),
-		// SLAPercentile:  0.99,
-		MetricsFile: "metrics.csv",
+		Seed:                  constants.DEFAULT_SEED,
+		NumKeys:               constants.DEFAULT_NUM_KEYS,
+		Endpoints:             []string{},
+		KeySize:               16,
+		ValueSize:             128,
+		ValueSizeDistribution: constants.VALUE_SIZE_FIXED,
+		ValueSizeStdDev:       32,
+		ValueSizeSigma:        1,
+		ValueSizeHistogram:    []string{},
+		ValueContent:          constants.VALUE_CONTENT_RANDOM,
+		WarmupDuration:        Duration(5 * time.Minute),
+		StepDuration:          Duration(1 * time.Minute),
+		TotalDuration:         Duration(30 * time.Minute),
+		InitialClients:        5,
+		ClientStepSize:        5,
+		MaxClients:            100,
+		MaxWaitTime:           Duration(500 * time.Millisecond),
+		WorkloadType:          constants.WORKLOAD_TYPE_READ_HEAVY,
+		Scenario:              constants.SCENARIO_KV_STORE,
+		LoadMode:              constants.LOAD_MODE_RAMP,
+		LoadSchedule:          []string{},
+		SpikeInterval:         Duration(5 * time.Minute),
+		SpikeDuration:         Duration(1 * time.Minute),
+		SinePeriod:            Duration(10 * time.Minute),
+		SLALatency:            Duration(100 * time.Millisecond),
+		SLAPercentile:         0.99,
+		SLAMaxErrorRate:       0.01,
+		LoopMode:              constants.LOOP_MODE_CLOSED,
+		InitialRate:           1000,
+		RateStepSize:          1000,
+		MaxRate:               10000,
+		OpenLoopWorkers:       100,
+		ThinkTime:             Duration(0),
+		ThinkTimeDistribution: constants.THINK_TIME_FIXED,
+		ClientRateLimit:       0,
+		KeyDistribution:       constants.KEY_DISTRIBUTION_UNIFORM,
+		ZipfianTheta:          0.99,
+		HotspotKeyPercent:     20,
+		HotspotOpPercent:      80,
+		ReadConsistency:       constants.READ_CONSISTENCY_LINEARIZABLE,
+		SerializablePercent:   50,
+		OperationMix:          []string{},
+		RangePercent:          0,
+		RangeLevel:            constants.RANGE_LEVEL_SHARD,
+		RangeMode:             constants.RANGE_MODE_FULL,
+		RangeLimit:            500,
+		ScanLength:            100,
+		RevisionLag:           1000,
+		HistoryDepth:          10,
+		LargeValueMin:         100 * 1024,
+		MaxRequestBytes:       1536 * 1024,
+		TxnKeys:               4,
+		TxnMaxRetries:         10,
+		WatchKeys:             100,
+		WatchWriteRate:        100,
+		WatchPrefixLevel:      constants.RANGE_LEVEL_SHARD,
+		LeaseTTL:              10,
+		LeasesPerClient:       10,
+		K8sObjects:            []string{"pods:5000", "configmaps:1000", "secrets:1000", "services:500", "deployments:500", "nodes:100"},
+		K8sNamespaces:         20,
+		K8sListLimit:          500,
+		K8sWatchesPerResource: 1,
+		K8sEventTTL:           3600,
+		K8sCompactionInterval: Duration(5 * time.Minute),
+		ReplayTraceFile:       "",
+		ReplaySpeed:           1,
+		MetricsFile:           constants.DEFAULT_METRICS_FILE,
 	}
 }
 
@@ -3,6 +3,8 @@ package config
 import (
 	"math/rand"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -114,6 +116,493 @@ func TestValidateConfig(t *testing.T) {
 			}(),
 			isErr: true,
 		},
+		{
+			name: "valid sla-search load mode",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.LoadMode = constants.LOAD_MODE_SLA_SEARCH
+				return cfg
+			}(),
+			isErr: false,
+		},
+		{
+			name: "invalid load mode",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.LoadMode = "exponential"
+				return cfg
+			}(),
+			isErr: true,
+		},
+		{
+			name: "sla-search load mode without SLA",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.LoadMode = constants.LOAD_MODE_SLA_SEARCH
+				cfg.SLALatency = 0
+				cfg.SLAPercentile = 0
+				return cfg
+			}(),
+			isErr: true,
+		},
+		{
+			name: "invalid SLA percentile",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.SLAPercentile = 99
+				return cfg
+			}(),
+			isErr: true,
+		},
+		{
+			name: "valid open-loop mode with load schedule",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.LoopMode = constants.LOOP_MODE_OPEN
+				cfg.LoadMode = constants.LOAD_MODE_STEPS
+				cfg.LoadSchedule = []string{"500@30s", "1000", "2000@1m"}
+				return cfg
+			}(),
+			isErr: false,
+		},
+		{
+			name: "open-loop mode with max rate below initial rate",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.LoopMode = constants.LOOP_MODE_OPEN
+				cfg.InitialRate = 5000
+				cfg.MaxRate = 1000
+				return cfg
+			}(),
+			isErr: true,
+		},
+		{
+			name: "steps load mode without load schedule",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.LoadMode = constants.LOAD_MODE_STEPS
+				return cfg
+			}(),
+			isErr: true,
+		},
+		{
+			name: "invalid load step",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.LoadMode = constants.LOAD_MODE_STEPS
+				cfg.LoadSchedule = []string{"10@forever"}
+				return cfg
+			}(),
+			isErr: true,
+		},
+		{
+			name: "spike load mode with spike longer than interval",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.LoadMode = constants.LOAD_MODE_SPIKE
+				cfg.SpikeDuration = cfg.SpikeInterval
+				return cfg
+			}(),
+			isErr: true,
+		},
+		{
+			name: "valid range reads",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.RangePercent = 20
+				cfg.RangeLevel = constants.RANGE_LEVEL_MIXED
+				cfg.RangeMode = constants.RANGE_MODE_KEYS_ONLY
+				return cfg
+			}(),
+			isErr: false,
+		},
+		{
+			name: "invalid range percent and level",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.RangePercent = 101
+				cfg.RangeLevel = "cluster"
+				return cfg
+			}(),
+			isErr: true,
+		},
+		{
+			name: "valid multi-key transactions",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.WorkloadType = constants.WORKLOAD_TYPE_TXN_MULTI
+				cfg.TxnConflictPercent = 10
+				return cfg
+			}(),
+			isErr: false,
+		},
+		{
+			name: "multi-key transactions without keys",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.WorkloadType = constants.WORKLOAD_TYPE_TXN_MULTI
+				cfg.TxnKeys = 0
+				return cfg
+			}(),
+			isErr: true,
+		},
+		{
+			name: "valid YCSB workload",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.WorkloadType = constants.WORKLOAD_TYPE_YCSB_D
+				return cfg
+			}(),
+			isErr: false,
+		},
+		{
+			name: "YCSB workload E without scan length",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.WorkloadType = constants.WORKLOAD_TYPE_YCSB_E
+				cfg.ScanLength = 0
+				return cfg
+			}(),
+			isErr: true,
+		},
+		{
+			name: "valid mixed read consistency",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.ReadConsistency = constants.READ_CONSISTENCY_MIXED
+				cfg.SerializablePercent = 80
+				return cfg
+			}(),
+			isErr: false,
+		},
+		{
+			name: "invalid read consistency",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.ReadConsistency = "sequential"
+				return cfg
+			}(),
+			isErr: true,
+		},
+		{
+			name: "valid MVCC history workload",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.WorkloadType = constants.WORKLOAD_TYPE_MVCC_HISTORY
+				cfg.RevisionLag = 0
+				return cfg
+			}(),
+			isErr: false,
+		},
+		{
+			name: "history walks without depth",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.OperationMix = []string{"put-prevkv:50", "history:50"}
+				cfg.HistoryDepth = 0
+				return cfg
+			}(),
+			isErr: true,
+		},
+		{
+			name: "valid large-object workload",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.WorkloadType = constants.WORKLOAD_TYPE_LARGE_OBJECT
+				cfg.NumKeys = 1000
+				return cfg
+			}(),
+			isErr: false,
+		},
+		{
+			name: "large-object workload with max request size below minimum",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.WorkloadType = constants.WORKLOAD_TYPE_LARGE_OBJECT
+				cfg.MaxRequestBytes = 64 * 1024
+				return cfg
+			}(),
+			isErr: true,
+		},
+		{
+			name: "valid operation mix",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.OperationMix = []string{"get:50:consistency=serializable", "put:20", "delete:5", "range:10:level=region:limit=100", "txn:10:keys=2", "insert:5"}
+				return cfg
+			}(),
+			isErr: false,
+		},
+		{
+			name: "operation mix with unknown parameter",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.OperationMix = []string{"get:90", "put:10:level=region"}
+				return cfg
+			}(),
+			isErr: true,
+		},
+		{
+			name: "operation mix in lock scenario",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.Scenario = constants.SCENARIO_LOCK_SERVICE
+				cfg.WorkloadType = constants.WORKLOAD_TYPE_LOCK_ONLY
+				cfg.OperationMix = []string{"get:100"}
+				return cfg
+			}(),
+			isErr: true,
+		},
+		{
+			name: "valid watch scenario",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.Scenario = constants.SCENARIO_WATCH
+				cfg.WorkloadType = constants.WORKLOAD_TYPE_WATCH_PREFIX
+				return cfg
+			}(),
+			isErr: false,
+		},
+		{
+			name: "watch scenario in open-loop mode",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.Scenario = constants.SCENARIO_WATCH
+				cfg.WorkloadType = constants.WORKLOAD_TYPE_WATCH_KEY
+				cfg.LoopMode = constants.LOOP_MODE_OPEN
+				return cfg
+			}(),
+			isErr: true,
+		},
+		{
+			name: "lease keepalive stream without leases",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.Scenario = constants.SCENARIO_LEASE
+				cfg.WorkloadType = constants.WORKLOAD_TYPE_LEASE_KEEPALIVE_STREAM
+				cfg.LeasesPerClient = 0
+				return cfg
+			}(),
+			isErr: true,
+		},
+		{
+			name: "valid lease scenario",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.Scenario = constants.SCENARIO_LEASE
+				cfg.WorkloadType = constants.WORKLOAD_TYPE_LEASE_KEEPALIVE
+				return cfg
+			}(),
+			isErr: false,
+		},
+		{
+			name: "lease scenario without TTL",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.Scenario = constants.SCENARIO_LEASE
+				cfg.WorkloadType = constants.WORKLOAD_TYPE_LEASE_EXPIRE
+				cfg.LeaseTTL = 0
+				return cfg
+			}(),
+			isErr: true,
+		},
+		{
+			name: "valid zipfian key distribution",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.KeyDistribution = constants.KEY_DISTRIBUTION_ZIPFIAN
+				return cfg
+			}(),
+			isErr: false,
+		},
+		{
+			name: "scrambled zipfian key distribution without theta",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.KeyDistribution = constants.KEY_DISTRIBUTION_SCRAMBLED_ZIPFIAN
+				cfg.ZipfianTheta = 0
+				return cfg
+			}(),
+			isErr: true,
+		},
+		{
+			name: "hotspot key distribution with all keys hot",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.KeyDistribution = constants.KEY_DISTRIBUTION_HOTSPOT
+				cfg.HotspotKeyPercent = 100
+				return cfg
+			}(),
+			isErr: true,
+		},
+		{
+			name: "valid lognormal value sizes with JSON content",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.ValueSizeDistribution = constants.VALUE_SIZE_LOGNORMAL
+				cfg.ValueSizeMax = 65536
+				cfg.ValueContent = constants.VALUE_CONTENT_JSON
+				return cfg
+			}(),
+			isErr: false,
+		},
+		{
+			name: "uniform value sizes without maximum",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.ValueSizeDistribution = constants.VALUE_SIZE_UNIFORM
+				cfg.ValueSizeMin = 64
+				return cfg
+			}(),
+			isErr: true,
+		},
+		{
+			name: "empirical value sizes without histogram",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.ValueSizeDistribution = constants.VALUE_SIZE_EMPIRICAL
+				return cfg
+			}(),
+			isErr: true,
+		},
+		{
+			name: "invalid value size bucket and content",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.ValueSizeDistribution = constants.VALUE_SIZE_EMPIRICAL
+				cfg.ValueSizeHistogram = []string{"1024"}
+				cfg.ValueContent = "xml"
+				return cfg
+			}(),
+			isErr: true,
+		},
+		{
+			name: "valid kubernetes scenario",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.Scenario = constants.SCENARIO_KUBERNETES
+				cfg.WorkloadType = constants.WORKLOAD_TYPE_APISERVER
+				cfg.K8sObjects = []string{"pods:100", "nodes:10"}
+				return cfg
+			}(),
+			isErr: false,
+		},
+		{
+			name: "kubernetes scenario with events as objects",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.Scenario = constants.SCENARIO_KUBERNETES
+				cfg.WorkloadType = constants.WORKLOAD_TYPE_APISERVER
+				cfg.K8sObjects = []string{"events:100"}
+				return cfg
+			}(),
+			isErr: true,
+		},
+		{
+			name: "kubernetes scenario with duplicate resource",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.Scenario = constants.SCENARIO_KUBERNETES
+				cfg.WorkloadType = constants.WORKLOAD_TYPE_APISERVER
+				cfg.K8sObjects = []string{"pods:100", "pods:10"}
+				return cfg
+			}(),
+			isErr: true,
+		},
+		{
+			name: "apiserver workload in kv-store scenario",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.WorkloadType = constants.WORKLOAD_TYPE_APISERVER
+				return cfg
+			}(),
+			isErr: true,
+		},
+		{
+			name: "valid replay scenario",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.Scenario = constants.SCENARIO_REPLAY
+				cfg.WorkloadType = constants.WORKLOAD_TYPE_TRACE
+				cfg.ReplayTraceFile = "trace.csv"
+				cfg.ReplaySpeed = 2
+				return cfg
+			}(),
+			isErr: false,
+		},
+		{
+			name: "replay scenario without trace",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.Scenario = constants.SCENARIO_REPLAY
+				cfg.WorkloadType = constants.WORKLOAD_TYPE_TRACE
+				return cfg
+			}(),
+			isErr: true,
+		},
+		{
+			name: "replay scenario in open loop",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.Scenario = constants.SCENARIO_REPLAY
+				cfg.WorkloadType = constants.WORKLOAD_TYPE_TRACE
+				cfg.ReplayTraceFile = "trace.csv"
+				cfg.LoopMode = constants.LOOP_MODE_OPEN
+				return cfg
+			}(),
+			isErr: true,
+		},
+		{
+			name: "valid think time and client rate limit",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.ThinkTime = Duration(50 * time.Millisecond)
+				cfg.ThinkTimeDistribution = constants.THINK_TIME_EXPONENTIAL
+				cfg.ClientRateLimit = 10
+				return cfg
+			}(),
+			isErr: false,
+		},
+		{
+			name: "invalid think time distribution",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.ThinkTime = Duration(50 * time.Millisecond)
+				cfg.ThinkTimeDistribution = "normal"
+				return cfg
+			}(),
+			isErr: true,
+		},
+		{
+			name: "think time in open loop",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.LoopMode = constants.LOOP_MODE_OPEN
+				cfg.ThinkTime = Duration(50 * time.Millisecond)
+				return cfg
+			}(),
+			isErr: true,
+		},
+		{
+			name: "client rate limit in open loop",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.LoopMode = constants.LOOP_MODE_OPEN
+				cfg.ClientRateLimit = 10
+				return cfg
+			}(),
+			isErr: true,
+		},
+		{
+			name: "invalid SLA error rate",
+			config: func() *BenchctlConfig {
+				cfg := GetDefaultConfig()
+				cfg.LoadMode = constants.LOAD_MODE_SLA_SEARCH
+				cfg.SLAMaxErrorRate = 1.5
+				return cfg
+			}(),
+			isErr: true,
+		},
 		{
 			name: "invalid key size",
 			config: func() *BenchctlConfig {
@@ -169,3 +658,216 @@ func TestReadConfig(t *testing.T) {
 		t.Error("ReadConfig() expected error for invalid JSON")
 	}
 }
+
+func TestParseOperationMix(t *testing.T) {
+	ops, err := ParseOperationMix([]string{"get:70", " range:20:mode=keys-only:level=domain", "txn:10:keys=3"})
+	if err != nil {
+		t.Fatalf("ParseOperationMix() error = %v", err)
+	}
+	want := []Operation{
+		{Name: constants.OPERATION_GET, Weight: 70, Params: map[string]string{}},
+		{Name: constants.OPERATION_RANGE, Weight: 20, Params: map[string]string{"mode": constants.RANGE_MODE_KEYS_ONLY, "level": constants.RANGE_LEVEL_DOMAIN}},
+		{Name: constants.OPERATION_TXN, Weight: 10, Params: map[string]string{"keys": "3"}},
+	}
+	if !reflect.DeepEqual(ops, want) {
+		t.Errorf("ParseOperationMix() = %v, want %v", ops, want)
+	}
+	if got := ops[1].String(); got != "range:20:level=domain:mode=keys-only" {
+		t.Errorf("Operation.String() = %q", got)
+	}
+
+	for _, mix := range [][]string{
+		{"lookup:10"},
+		{"get"},
+		{"get:-1"},
+		{"get:0", "put:0"},
+		{"txn:10:keys=129"},
+		{"range:10:limit"},
+	} {
+		if _, err := ParseOperationMix(mix); err == nil {
+			t.Errorf("ParseOperationMix(%v) expected error", mix)
+		}
+	}
+}
+
+func TestReadLoadProfileCSV(t *testing.T) {
+	// Test reading a valid profile with a header row
+	profileFile := t.TempDir() + "/profile.csv"
+	err := os.WriteFile(profileFile, []byte("duration,level\n30s,100\n# peak\n1m, 400\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to write test profile: %v", err)
+	}
+
+	steps, err := ReadLoadProfileCSV(profileFile)
+	if err != nil {
+		t.Fatalf("ReadLoadProfileCSV() error = %v", err)
+	}
+	if want := []string{"100@30s", "400@1m0s"}; !reflect.DeepEqual(steps, want) {
+		t.Errorf("ReadLoadProfileCSV() = %v, want %v", steps, want)
+	}
+
+	// Test reading a profile with an invalid level
+	invalidFile := t.TempDir() + "/invalid.csv"
+	err = os.WriteFile(invalidFile, []byte("30s,-1\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to write invalid profile: %v", err)
+	}
+
+	_, err = ReadLoadProfileCSV(invalidFile)
+	if err == nil {
+		t.Error("ReadLoadProfileCSV() expected error for invalid level")
+	}
+}
+
+func TestReadValueSizeHistogramCSV(t *testing.T) {
+	// Test reading a valid histogram with a header row
+	histogramFile := t.TempDir() + "/sizes.csv"
+	err := os.WriteFile(histogramFile, []byte("size,weight\n512,70\n# large objects\n65536, 30\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to write test histogram: %v", err)
+	}
+
+	buckets, err := ReadValueSizeHistogramCSV(histogramFile)
+	if err != nil {
+		t.Fatalf("ReadValueSizeHistogramCSV() error = %v", err)
+	}
+	if want := []string{"512:70", "65536:30"}; !reflect.DeepEqual(buckets, want) {
+		t.Errorf("ReadValueSizeHistogramCSV() = %v, want %v", buckets, want)
+	}
+	if _, err := ParseValueSizeHistogram(buckets); err != nil {
+		t.Errorf("ParseValueSizeHistogram() error = %v", err)
+	}
+
+	// Test parsing a histogram without weight
+	if _, err := ParseValueSizeHistogram([]string{"512:0"}); err == nil {
+		t.Error("ParseValueSizeHistogram() expected error for zero total weight")
+	}
+}
+
+func TestReadTrace(t *testing.T) {
+	// Test reading a trace with a header row, the records are sorted by timestamp
+	trace := "timestamp,op,key,value_size,range_end,lease\n" +
+		"2000,put,/a,128,,30\n" +
+		"# reads\n" +
+		"1000,range,/a,,/b,\n" +
+		"3000,get,/a,,,\n"
+	records, err := ReadTrace(strings.NewReader(trace))
+	if err != nil {
+		t.Fatalf("ReadTrace() error = %v", err)
+	}
+	want := []TraceRecord{
+		{Timestamp: 1000, Op: constants.OPERATION_RANGE, Key: "/a", ValueSize: -1, RangeEnd: "/b"},
+		{Timestamp: 2000, Op: constants.OPERATION_PUT, Key: "/a", ValueSize: 128, Lease: 30},
+		{Timestamp: 3000, Op: constants.OPERATION_GET, Key: "/a", ValueSize: -1},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("ReadTrace() = %v, want %v", records, want)
+	}
+
+	// Test reading invalid records
+	for _, row := range []string{
+		"x,get,/a,,,",
+		"1000,txn,/a,,,",
+		"1000,range,/a,,,",
+		"1000,put,/a,,/b,",
+		"1000,put,/a,-1,,",
+		"1000,get,,,,",
+	} {
+		if _, err := ReadTrace(strings.NewReader(row + "\n")); err == nil {
+			t.Errorf("ReadTrace() expected error for %q", row)
+		}
+	}
+	if _, err := ReadTrace(strings.NewReader("timestamp,op,key,value_size,range_end,lease\n")); err == nil {
+		t.Error("ReadTrace() expected error for empty trace")
+	}
+}
+
+func TestConvertMetrics(t *testing.T) {
+	metrics := "# csb-metrics format_version=2\n" +
+		"unix_timestamp_nano,key,operation,latency_us,success,request_bytes,ttl_s\n" +
+		"5000000,/k1,write,1000,true,10,\n" +
+		"6000000,/k,range,2000,true,0,\n" +
+		"7000000,/k1,watch,10,true,0,\n" +
+		"8000000,/k2,grant,1000,true,7,60\n"
+	records, skipped, err := ConvertMetrics(strings.NewReader(metrics))
+	if err != nil {
+		t.Fatalf("ConvertMetrics() error = %v", err)
+	}
+	if skipped != 1 {
+		t.Errorf("ConvertMetrics() skipped = %d, want 1", skipped)
+	}
+	want := []TraceRecord{
+		{Timestamp: 4000000, Op: constants.OPERATION_PUT, Key: "/k1", ValueSize: 7},
+		{Timestamp: 4000000, Op: constants.OPERATION_RANGE, Key: "/k", ValueSize: -1, RangeEnd: "/l"},
+		{Timestamp: 7000000, Op: constants.OPERATION_PUT, Key: "/k2", ValueSize: 4, Lease: 60},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("ConvertMetrics() = %v, want %v", records, want)
+	}
+
+	// Test the round trip through a trace file
+	var buf strings.Builder
+	if err := WriteTrace(&buf, records); err != nil {
+		t.Fatalf("WriteTrace() error = %v", err)
+	}
+	read, err := ReadTrace(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("ReadTrace() error = %v", err)
+	}
+	if !reflect.DeepEqual(read, want) {
+		t.Errorf("ReadTrace() = %v, want %v", read, want)
+	}
+
+	// Test converting a metrics file without format marker, which records
+	// the latencies in milliseconds
+	legacy := "unix_timestamp_nano,key,operation,latency_ms,success,status_code,status_text,num_clients,client_id,run_phase\n" +
+		"5000000,/k1,read,2,true,0,,1,0,main\n"
+	records, skipped, err = ConvertMetrics(strings.NewReader(legacy))
+	if err != nil {
+		t.Fatalf("ConvertMetrics() error = %v", err)
+	}
+	want = []TraceRecord{{Timestamp: 3000000, Op: constants.OPERATION_GET, Key: "/k1", ValueSize: -1}}
+	if skipped != 0 || !reflect.DeepEqual(records, want) {
+		t.Errorf("ConvertMetrics() = %v, %d, want %v, 0", records, skipped, want)
+	}
+}
+
+func TestMaxWorkers(t *testing.T) {
+	tests := []struct {
+		name   string
+		config func(cfg *BenchctlConfig)
+		want   int
+	}{
+		{
+			name:   "ramp bounded by max clients",
+			config: func(cfg *BenchctlConfig) {},
+			want:   100,
+		},
+		{
+			name: "load schedule above max clients",
+			config: func(cfg *BenchctlConfig) {
+				cfg.LoadMode = constants.LOAD_MODE_STEPS
+				cfg.LoadSchedule = []string{"50", "250@30s", "10"}
+			},
+			want: 250,
+		},
+		{
+			name: "open-loop workers",
+			config: func(cfg *BenchctlConfig) {
+				cfg.LoopMode = constants.LOOP_MODE_OPEN
+				cfg.OpenLoopWorkers = 400
+			},
+			want: 400,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := GetDefaultConfig()
+			tt.config(cfg)
+			if got := MaxWorkers(cfg); got != tt.want {
+				t.Errorf("MaxWorkers() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
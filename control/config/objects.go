@@ -0,0 +1,65 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Resource types are lower-case names like in the keys of the kube-apiserver,
+// e.g. "pods" or "customresourcedefinitions"
+var resourceNamePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9.-]*[a-z0-9])?$`)
+
+// ObjectCount is the number of objects of a resource type of the kubernetes
+// scenario
+type ObjectCount struct {
+	Resource string
+	Count    int
+}
+
+// ParseObjectCount parses an object count in the format "resource:count",
+// e.g. "pods:5000". Events are created during the run, so they cannot be
+// loaded.
+func ParseObjectCount(s string) (ObjectCount, error) {
+	resource, countStr, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if !ok {
+		return ObjectCount{}, fmt.Errorf("object count %q is not in the format resource:count", s)
+	}
+	if !resourceNamePattern.MatchString(resource) {
+		return ObjectCount{}, fmt.Errorf("invalid resource in object count %q", s)
+	}
+	if resource == "events" {
+		return ObjectCount{}, fmt.Errorf("events are created during the run, object count %q", s)
+	}
+	count, err := strconv.Atoi(countStr)
+	if err != nil || count < 0 {
+		return ObjectCount{}, fmt.Errorf("invalid count in object count %q", s)
+	}
+	return ObjectCount{Resource: resource, Count: count}, nil
+}
+
+// ParseObjectCounts parses the object counts of all resource types, every
+// resource type may only be listed once and there has to be an object
+func ParseObjectCounts(counts []string) ([]ObjectCount, error) {
+	objects := make([]ObjectCount, 0, len(counts))
+	seen := make(map[string]bool)
+	total := 0
+	for _, s := range counts {
+		count, err := ParseObjectCount(s)
+		if err != nil {
+			return nil, err
+		}
+		if seen[count.Resource] {
+			return nil, fmt.Errorf("resource %s is listed more than once", count.Resource)
+		}
+		seen[count.Resource] = true
+		total += count.Count
+		objects = append(objects, count)
+	}
+	if total == 0 {
+		return nil, errors.New("there are no objects")
+	}
+	return objects, nil
+}
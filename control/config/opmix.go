@@ -0,0 +1,137 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"csb/control/constants"
+)
+
+// Operation is a weighted operation of the operation mix, every operation
+// is picked with the probability of its weight relative to the sum of all
+// weights. The parameters override the config of the operation, e.g. the
+// range level of a range read.
+type Operation struct {
+	Name   string
+	Weight int
+	Params map[string]string
+}
+
+// parameters accepted by the operations
+var operationParams = map[string][]string{
+	constants.OPERATION_GET:    {"consistency"},
+	constants.OPERATION_PUT:    {},
+	constants.OPERATION_DELETE: {},
+	constants.OPERATION_RANGE:  {"level", "mode", "limit", "consistency"},
+	constants.OPERATION_TXN:    {"keys"},
+	constants.OPERATION_INSERT: {},
+	constants.OPERATION_SCAN:   {"length", "consistency"},
+	constants.OPERATION_RMW:    {},
+	// MVCC operations
+	constants.OPERATION_GET_REV:    {"lag"},
+	constants.OPERATION_PUT_PREVKV: {},
+	constants.OPERATION_HISTORY:    {"depth"},
+}
+
+// ParseOperation parses an operation in the format
+// "name:weight[:param=value...]", e.g. "range:10:level=region:limit=100"
+func ParseOperation(s string) (Operation, error) {
+	fields := strings.Split(strings.TrimSpace(s), ":")
+	params, ok := operationParams[fields[0]]
+	if !ok {
+		return Operation{}, fmt.Errorf("unknown operation in %q", s)
+	}
+	if len(fields) < 2 {
+		return Operation{}, fmt.Errorf("missing weight in operation %q", s)
+	}
+	weight, err := strconv.Atoi(fields[1])
+	if err != nil || weight < 0 {
+		return Operation{}, fmt.Errorf("invalid weight in operation %q", s)
+	}
+
+	op := Operation{Name: fields[0], Weight: weight, Params: make(map[string]string)}
+	for _, param := range fields[2:] {
+		name, value, found := strings.Cut(param, "=")
+		if !found || !slices.Contains(params, name) {
+			return Operation{}, fmt.Errorf("invalid parameter %q in operation %q", param, s)
+		}
+		if err := validateOperationParam(name, value); err != nil {
+			return Operation{}, fmt.Errorf("invalid parameter %q in operation %q: %w", param, s, err)
+		}
+		op.Params[name] = value
+	}
+	return op, nil
+}
+
+// String returns the operation in the format accepted by ParseOperation
+func (op Operation) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s:%d", op.Name, op.Weight)
+	// the parameters are written in the order they are documented
+	for _, name := range operationParams[op.Name] {
+		if value, ok := op.Params[name]; ok {
+			fmt.Fprintf(&sb, ":%s=%s", name, value)
+		}
+	}
+	return sb.String()
+}
+
+func validateOperationParam(name string, value string) error {
+	switch name {
+	case "level":
+		if !slices.Contains([]string{constants.RANGE_LEVEL_DOMAIN, constants.RANGE_LEVEL_REGION, constants.RANGE_LEVEL_SHARD, constants.RANGE_LEVEL_MIXED}, value) {
+			return fmt.Errorf("unknown range level %s", value)
+		}
+	case "mode":
+		if !slices.Contains([]string{constants.RANGE_MODE_FULL, constants.RANGE_MODE_KEYS_ONLY, constants.RANGE_MODE_COUNT_ONLY}, value) {
+			return fmt.Errorf("unknown range mode %s", value)
+		}
+	case "limit":
+		if limit, err := strconv.ParseInt(value, 10, 64); err != nil || limit < 0 {
+			return fmt.Errorf("limit must be a number of at least 0")
+		}
+	case "length":
+		if length, err := strconv.Atoi(value); err != nil || length <= 0 {
+			return fmt.Errorf("length must be a number of at least 1")
+		}
+	case "lag":
+		if lag, err := strconv.ParseInt(value, 10, 64); err != nil || lag < 0 {
+			return fmt.Errorf("lag must be a number of at least 0")
+		}
+	case "depth":
+		if depth, err := strconv.Atoi(value); err != nil || depth <= 0 {
+			return fmt.Errorf("depth must be a number of at least 1")
+		}
+	case "consistency":
+		if !slices.Contains([]string{constants.READ_CONSISTENCY_LINEARIZABLE, constants.READ_CONSISTENCY_SERIALIZABLE, constants.READ_CONSISTENCY_MIXED}, value) {
+			return fmt.Errorf("unknown read consistency %s", value)
+		}
+	case "keys":
+		// etcd allows 128 operations per transaction by default
+		if keys, err := strconv.Atoi(value); err != nil || keys <= 0 || keys > 128 {
+			return fmt.Errorf("keys must be a number between 1 and 128")
+		}
+	}
+	return nil
+}
+
+// ParseOperationMix parses all operations of an operation mix, the sum of
+// the weights has to be positive
+func ParseOperationMix(mix []string) ([]Operation, error) {
+	ops := make([]Operation, 0, len(mix))
+	totalWeight := 0
+	for _, s := range mix {
+		op, err := ParseOperation(s)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, op)
+		totalWeight += op.Weight
+	}
+	if totalWeight == 0 {
+		return nil, fmt.Errorf("operation mix has no operation with a weight above 0")
+	}
+	return ops, nil
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"csb/control/constants"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LoadStep is a single step of a load schedule, the level is the number of
+// clients in closed-loop mode and the target rate in open-loop mode. A step
+// without a duration lasts for the configured step duration.
+type LoadStep struct {
+	Level    int
+	Duration time.Duration
+}
+
+// ParseLoadStep parses a load step in the format "level[@duration]", e.g. "100@30s"
+func ParseLoadStep(s string) (LoadStep, error) {
+	levelStr, durationStr, hasDuration := strings.Cut(strings.TrimSpace(s), "@")
+	level, err := strconv.Atoi(levelStr)
+	if err != nil || level <= 0 {
+		return LoadStep{}, fmt.Errorf("invalid level in load step %q", s)
+	}
+	step := LoadStep{Level: level}
+	if hasDuration {
+		step.Duration, err = time.ParseDuration(durationStr)
+		if err != nil || step.Duration <= 0 {
+			return LoadStep{}, fmt.Errorf("invalid duration in load step %q", s)
+		}
+	}
+	return step, nil
+}
+
+// String returns the load step in the format accepted by ParseLoadStep
+func (s LoadStep) String() string {
+	if s.Duration == 0 {
+		return strconv.Itoa(s.Level)
+	}
+	return fmt.Sprintf("%d@%v", s.Level, s.Duration)
+}
+
+// ParseLoadSchedule parses all steps of a load schedule
+func ParseLoadSchedule(steps []string) ([]LoadStep, error) {
+	schedule := make([]LoadStep, 0, len(steps))
+	for _, s := range steps {
+		step, err := ParseLoadStep(s)
+		if err != nil {
+			return nil, err
+		}
+		schedule = append(schedule, step)
+	}
+	return schedule, nil
+}
+
+// MaxWorkers returns the largest number of workers a benchmark client runs
+// in a step of the load profile. In open-loop mode every step runs the
+// open-loop workers, the levels of a load schedule are not bounded by the
+// maximum number of clients. The load schedule of the "csv" load mode has
+// to be filled in before.
+func MaxWorkers(cfg *BenchctlConfig) int {
+	if cfg.LoopMode == constants.LOOP_MODE_OPEN {
+		return max(cfg.OpenLoopWorkers, 1)
+	}
+	workers := max(cfg.MaxClients, cfg.InitialClients, 1)
+	if cfg.LoadMode == constants.LOAD_MODE_STEPS || cfg.LoadMode == constants.LOAD_MODE_CSV {
+		if steps, err := ParseLoadSchedule(cfg.LoadSchedule); err == nil {
+			for _, step := range steps {
+				workers = max(workers, step.Level)
+			}
+		}
+	}
+	return workers
+}
+
+// ReadLoadProfileCSV reads a load schedule from a CSV file with one step per
+// row and the columns duration and level, e.g. "30s,100". A header row is
+// skipped. The steps are returned in the format of the load schedule.
+func ReadLoadProfileCSV(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = 2
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+
+	steps := make([]string, 0)
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if row == 1 && strings.EqualFold(record[0], "duration") {
+			continue
+		}
+		step, err := ParseLoadStep(record[1] + "@" + record[0])
+		if err != nil {
+			return nil, fmt.Errorf("row %d of %s: %w", row, path, err)
+		}
+		steps = append(steps, step.String())
+	}
+	if len(steps) == 0 {
+		return nil, errors.New("load profile file contains no steps")
+	}
+	return steps, nil
+}
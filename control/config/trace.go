@@ -0,0 +1,257 @@
+package config
+
+import (
+	"bufio"
+	"cmp"
+	"csb/control/constants"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// TraceRecord is an operation of a recorded trace, which the replay scenario
+// issues against the cluster again
+type TraceRecord struct {
+	Timestamp int64  // unix time of the original operation in nanoseconds
+	Op        string // get, put, delete or range
+	Key       string
+	ValueSize int    // size of the value of a put, -1 for the configured value sizes
+	RangeEnd  string // end of the range of a get, range or delete, empty for a single key
+	Lease     int64  // TTL in seconds of the lease of a put, 0 without a lease
+}
+
+// Columns of a trace file
+var traceHeader = []string{"timestamp", "op", "key", "value_size", "range_end", "lease"}
+
+// ParseTraceRecord parses a row of a trace file, an empty value size is the
+// same as -1
+func ParseTraceRecord(row []string) (TraceRecord, error) {
+	if len(row) != len(traceHeader) {
+		return TraceRecord{}, fmt.Errorf("trace record has %d fields, expected %d", len(row), len(traceHeader))
+	}
+	record := TraceRecord{Op: row[1], Key: row[2], ValueSize: -1, RangeEnd: row[4]}
+	var err error
+	if record.Timestamp, err = strconv.ParseInt(row[0], 10, 64); err != nil {
+		return TraceRecord{}, fmt.Errorf("invalid timestamp %q", row[0])
+	}
+	switch record.Op {
+	case constants.OPERATION_GET, constants.OPERATION_PUT, constants.OPERATION_DELETE:
+	case constants.OPERATION_RANGE:
+		if record.RangeEnd == "" {
+			return TraceRecord{}, errors.New("range without range end")
+		}
+	default:
+		return TraceRecord{}, fmt.Errorf("unsupported operation %q", record.Op)
+	}
+	if record.Key == "" {
+		return TraceRecord{}, errors.New("empty key")
+	}
+	if row[3] != "" {
+		if record.ValueSize, err = strconv.Atoi(row[3]); err != nil || record.ValueSize < 0 {
+			return TraceRecord{}, fmt.Errorf("invalid value size %q", row[3])
+		}
+	}
+	if row[5] != "" {
+		if record.Lease, err = strconv.ParseInt(row[5], 10, 64); err != nil || record.Lease < 0 {
+			return TraceRecord{}, fmt.Errorf("invalid lease TTL %q", row[5])
+		}
+	}
+	if record.Op == constants.OPERATION_PUT && record.RangeEnd != "" {
+		return TraceRecord{}, errors.New("put with range end")
+	}
+	return record, nil
+}
+
+// Row returns the fields of the record in a trace file
+func (r TraceRecord) Row() []string {
+	valueSize, lease := "", ""
+	if r.ValueSize >= 0 {
+		valueSize = strconv.Itoa(r.ValueSize)
+	}
+	if r.Lease > 0 {
+		lease = strconv.FormatInt(r.Lease, 10)
+	}
+	return []string{strconv.FormatInt(r.Timestamp, 10), r.Op, r.Key, valueSize, r.RangeEnd, lease}
+}
+
+// ReadTrace reads the records of a trace in CSV format with the columns
+// timestamp, op, key, value_size, range_end and lease. The header row is
+// optional, the records are sorted by their timestamps.
+func ReadTrace(r io.Reader) ([]TraceRecord, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = len(traceHeader)
+	reader.Comment = '#'
+
+	records := make([]TraceRecord, 0)
+	for row := 1; ; row++ {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if row == 1 && fields[0] == traceHeader[0] {
+			continue
+		}
+		record, err := ParseTraceRecord(fields)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", row, err)
+		}
+		records = append(records, record)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("trace contains no records")
+	}
+	slices.SortStableFunc(records, func(a, b TraceRecord) int {
+		return cmp.Compare(a.Timestamp, b.Timestamp)
+	})
+	return records, nil
+}
+
+// ReadTraceCSV reads the records of a trace file
+func ReadTraceCSV(path string) ([]TraceRecord, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	records, err := ReadTrace(file)
+	if err != nil {
+		return nil, fmt.Errorf("trace %s: %w", path, err)
+	}
+	return records, nil
+}
+
+// WriteTrace writes the records of a trace in CSV format with a header row
+func WriteTrace(w io.Writer, records []TraceRecord) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(traceHeader); err != nil {
+		return err
+	}
+	for _, record := range records {
+		if err := writer.Write(record.Row()); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// Operations of the metrics files by the operation of the trace they are
+// replayed as, the other operations like watches, locks and transactions
+// cannot be expressed in a trace
+var traceOperations = map[string]string{
+	"read":                         constants.OPERATION_GET,
+	constants.OPERATION_GET:        constants.OPERATION_GET,
+	constants.OPERATION_GET_REV:    constants.OPERATION_GET,
+	constants.OPERATION_HISTORY:    constants.OPERATION_GET,
+	"write":                        constants.OPERATION_PUT,
+	constants.OPERATION_PUT:        constants.OPERATION_PUT,
+	constants.OPERATION_PUT_PREVKV: constants.OPERATION_PUT,
+	constants.OPERATION_INSERT:     constants.OPERATION_PUT,
+	constants.OPERATION_RMW:        constants.OPERATION_PUT,
+	"update":                       constants.OPERATION_PUT,
+	"create":                       constants.OPERATION_PUT,
+	"event":                        constants.OPERATION_PUT,
+	"grant":                        constants.OPERATION_PUT,
+	"grant-revoke":                 constants.OPERATION_PUT,
+	constants.OPERATION_DELETE:     constants.OPERATION_DELETE,
+	constants.OPERATION_RANGE:      constants.OPERATION_RANGE,
+	"list":                         constants.OPERATION_RANGE,
+}
+
+// ConvertMetrics turns the operations of a metrics file into trace records.
+// An operation starts at its timestamp minus its latency, the value sizes
+// are derived from the request bytes and the lease TTLs from the ttl_s
+// column of the lease scenario. Range reads scan the keys below the prefix
+// in the key column. Metrics files without the format marker record the
+// latencies in milliseconds. It returns the records in the order of the
+// metrics file and the number of operations which cannot be replayed.
+func ConvertMetrics(r io.Reader) ([]TraceRecord, int, error) {
+	buffered := bufio.NewReader(r)
+	latencyColumn, latencyUnit := "latency_us", time.Microsecond
+	if marker, _ := buffered.Peek(len(constants.METRICS_FORMAT_MARKER)); string(marker) != constants.METRICS_FORMAT_MARKER {
+		latencyColumn, latencyUnit = "latency_ms", time.Millisecond
+	}
+	reader := csv.NewReader(buffered)
+	reader.Comment = '#'
+	header, err := reader.Read()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[name] = i
+	}
+	for _, name := range []string{"unix_timestamp_nano", "key", "operation", latencyColumn} {
+		if _, ok := columns[name]; !ok {
+			return nil, 0, fmt.Errorf("metrics file has no column %s", name)
+		}
+	}
+	requestBytes, hasRequestBytes := columns["request_bytes"]
+	ttl, hasTTL := columns["ttl_s"]
+
+	records := make([]TraceRecord, 0)
+	skipped := 0
+	for row := 2; ; row++ {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		op, ok := traceOperations[fields[columns["operation"]]]
+		key := fields[columns["key"]]
+		if !ok || key == "" {
+			skipped++
+			continue
+		}
+		timestamp, err := strconv.ParseInt(fields[columns["unix_timestamp_nano"]], 10, 64)
+		if err != nil {
+			return nil, 0, fmt.Errorf("row %d: invalid timestamp", row)
+		}
+		latency, err := strconv.ParseInt(fields[columns[latencyColumn]], 10, 64)
+		if err != nil {
+			return nil, 0, fmt.Errorf("row %d: invalid latency", row)
+		}
+		record := TraceRecord{Timestamp: timestamp - latency*int64(latencyUnit), Op: op, Key: key, ValueSize: -1}
+		switch op {
+		case constants.OPERATION_PUT:
+			if hasRequestBytes {
+				// failed writes have no request bytes
+				if n, err := strconv.Atoi(fields[requestBytes]); err == nil && n >= len(key) && n > 0 {
+					record.ValueSize = n - len(key)
+				}
+			}
+			if hasTTL {
+				record.Lease, _ = strconv.ParseInt(fields[ttl], 10, 64)
+			}
+		case constants.OPERATION_RANGE:
+			record.RangeEnd = prefixRangeEnd(key)
+		}
+		records = append(records, record)
+	}
+	return records, skipped, nil
+}
+
+// prefixRangeEnd returns the end of the range of all keys with the prefix,
+// like clientv3.GetPrefixRangeEnd
+func prefixRangeEnd(prefix string) string {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return string(end[:i+1])
+		}
+	}
+	// the prefix consists of 0xff bytes only, the range ends with the
+	// last key
+	return "\x00"
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ValueSizeBucket is a bucket of an empirical value size histogram, values
+// of the size are drawn in proportion to the weight of the bucket
+type ValueSizeBucket struct {
+	Size   int
+	Weight int
+}
+
+// ParseValueSizeBucket parses a histogram bucket in the format
+// "size:weight", e.g. "4096:25"
+func ParseValueSizeBucket(s string) (ValueSizeBucket, error) {
+	sizeStr, weightStr, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if !ok {
+		return ValueSizeBucket{}, fmt.Errorf("value size bucket %q is not in the format size:weight", s)
+	}
+	size, err := strconv.Atoi(strings.TrimSpace(sizeStr))
+	if err != nil || size < 0 {
+		return ValueSizeBucket{}, fmt.Errorf("invalid size in value size bucket %q", s)
+	}
+	weight, err := strconv.Atoi(strings.TrimSpace(weightStr))
+	if err != nil || weight < 0 {
+		return ValueSizeBucket{}, fmt.Errorf("invalid weight in value size bucket %q", s)
+	}
+	return ValueSizeBucket{Size: size, Weight: weight}, nil
+}
+
+// String returns the bucket in the format accepted by ParseValueSizeBucket
+func (b ValueSizeBucket) String() string {
+	return fmt.Sprintf("%d:%d", b.Size, b.Weight)
+}
+
+// ParseValueSizeHistogram parses all buckets of a value size histogram, the
+// total weight has to be positive
+func ParseValueSizeHistogram(buckets []string) ([]ValueSizeBucket, error) {
+	histogram := make([]ValueSizeBucket, 0, len(buckets))
+	totalWeight := 0
+	for _, s := range buckets {
+		bucket, err := ParseValueSizeBucket(s)
+		if err != nil {
+			return nil, err
+		}
+		totalWeight += bucket.Weight
+		histogram = append(histogram, bucket)
+	}
+	if totalWeight <= 0 {
+		return nil, errors.New("value size histogram has no positive weight")
+	}
+	return histogram, nil
+}
+
+// ReadValueSizeHistogramCSV reads a value size histogram from a CSV file
+// with one bucket per row and the columns size and weight, e.g. "4096,25".
+// A header row is skipped. The buckets are returned in the format of the
+// value size histogram.
+func ReadValueSizeHistogramCSV(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = 2
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+
+	buckets := make([]string, 0)
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if row == 1 && strings.EqualFold(record[0], "size") {
+			continue
+		}
+		bucket, err := ParseValueSizeBucket(record[0] + ":" + record[1])
+		if err != nil {
+			return nil, fmt.Errorf("row %d of %s: %w", row, path, err)
+		}
+		buckets = append(buckets, bucket.String())
+	}
+	if len(buckets) == 0 {
+		return nil, errors.New("value size file contains no buckets")
+	}
+	return buckets, nil
+}
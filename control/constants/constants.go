@@ -12,11 +12,27 @@ const (
 	// Different scenarios that can be run with corresponding workload types
 	SCENARIO_KV_STORE     = "kv-store"
 	SCENARIO_LOCK_SERVICE = "lock-service"
+	SCENARIO_WATCH        = "watch"
+	SCENARIO_LEASE        = "lease"
+	SCENARIO_KUBERNETES   = "kubernetes"
+	SCENARIO_REPLAY       = "replay"
 
 	// The following workload types are specific to the kv-store scenario
 	WORKLOAD_TYPE_READ_HEAVY   = "read-heavy"   // 95% reads, 5% writes
 	WORKLOAD_TYPE_UPDATE_HEAVY = "update-heavy" // 50% reads, 50% writes
 	WORKLOAD_TYPE_READ_ONLY    = "read-only"    // 100% reads
+	WORKLOAD_TYPE_TXN_CAS      = "txn-cas"      // compare-and-swap of a single key on its mod revision
+	WORKLOAD_TYPE_TXN_MULTI    = "txn-multi"    // atomic update of several keys within one transaction
+	WORKLOAD_TYPE_READ_COMPARE = "read-compare" // linearizable and serializable reads of the same keys
+	WORKLOAD_TYPE_MVCC_HISTORY = "mvcc-history" // reads of past revisions and of the history of keys
+	WORKLOAD_TYPE_LARGE_OBJECT = "large-object" // reads and writes of values up to the max request size
+	// YCSB core workloads, the keys follow the request distribution of YCSB
+	WORKLOAD_TYPE_YCSB_A = "ycsb-a" // 50% reads, 50% updates, zipfian
+	WORKLOAD_TYPE_YCSB_B = "ycsb-b" // 95% reads, 5% updates, zipfian
+	WORKLOAD_TYPE_YCSB_C = "ycsb-c" // 100% reads, zipfian
+	WORKLOAD_TYPE_YCSB_D = "ycsb-d" // 95% reads, 5% inserts, latest
+	WORKLOAD_TYPE_YCSB_E = "ycsb-e" // 95% short scans, 5% inserts, zipfian
+	WORKLOAD_TYPE_YCSB_F = "ycsb-f" // 50% reads, 50% read-modify-writes, zipfian
 
 	// The following workload types are specific to the lock-service scenario
 	WORKLOAD_TYPE_LOCK_ONLY        = "lock-only"        // 100% lock operations
@@ -24,10 +40,109 @@ const (
 	WORKLOAD_TYPE_LOCK_MIXED_WRITE = "lock-mixed-write" // all read/write opeartions performed under lock
 	WORKLOAD_TYPE_LOCK_CONTENTION  = "lock-contention"  // all clients contending for a  set of locks
 
+	// The following workload types are specific to the watch scenario
+	WORKLOAD_TYPE_WATCH_KEY    = "watch-key"    // every watcher watches a single key
+	WORKLOAD_TYPE_WATCH_PREFIX = "watch-prefix" // every watcher watches a prefix of the key hierarchy
+
+	// The following workload types are specific to the lease scenario
+	WORKLOAD_TYPE_LEASE_GRANT_REVOKE     = "lease-grant-revoke"     // grant a lease, attach a key and revoke it
+	WORKLOAD_TYPE_LEASE_KEEPALIVE        = "lease-keepalive"        // keep a set of leases per client alive
+	WORKLOAD_TYPE_LEASE_KEEPALIVE_STREAM = "lease-keepalive-stream" // keep a set of leases per client alive with a keepalive stream
+	WORKLOAD_TYPE_LEASE_EXPIRE           = "lease-expire"           // let a lease expire and wait for the deletion of its key
+
+	// The following workload types are specific to the kubernetes scenario
+	WORKLOAD_TYPE_APISERVER = "apiserver" // access pattern of the kube-apiserver on the objects of a cluster
+
+	// The following workload types are specific to the replay scenario
+	WORKLOAD_TYPE_TRACE = "trace" // operations of a recorded trace at their original timing
+
+	// Load modes (load profiles) controlling the load level of the main
+	// benchmark steps, the number of clients or the target rate
+	LOAD_MODE_RAMP         = "ramp"         // start with the initial level and increase it every step
+	LOAD_MODE_CONSTANT     = "constant"     // keep the initial level
+	LOAD_MODE_RAMP_UP_DOWN = "ramp-up-down" // increase the level up to the maximum, then decrease it again
+	LOAD_MODE_STEPS        = "steps"        // run the steps of the load schedule
+	LOAD_MODE_SPIKE        = "spike"        // keep the initial level with periodic spikes at the maximum level
+	LOAD_MODE_SINE         = "sine"         // move the level between initial and maximum along a sine wave
+	LOAD_MODE_CSV          = "csv"          // run the steps of a CSV file
+	LOAD_MODE_SLA_SEARCH   = "sla-search"   // search for the highest load that stays within the SLA
+
+	// Loop modes controlling how requests are issued within a step
+	LOOP_MODE_CLOSED = "closed-loop" // every client issues its next request once the previous one returned
+	LOOP_MODE_OPEN   = "open-loop"   // requests are dispatched at a target rate regardless of the responses
+
+	// Think time distributions, how long a client pauses between its
+	// operations in closed-loop mode
+	THINK_TIME_FIXED       = "fixed"       // every pause lasts the think time
+	THINK_TIME_EXPONENTIAL = "exponential" // exponentially distributed with the think time as mean
+	THINK_TIME_UNIFORM     = "uniform"     // uniformly distributed between 0 and twice the think time
+
+	// Levels of the key hierarchy scanned by the range reads of the kv-store
+	// scenario, e.g. "/prd/", "/prd/eu4/" and "/prd/eu4/637" for the key "/prd/eu4/637cGxJ"
+	RANGE_LEVEL_DOMAIN = "domain"
+	RANGE_LEVEL_REGION = "region"
+	RANGE_LEVEL_SHARD  = "shard"
+	RANGE_LEVEL_MIXED  = "mixed" // pick one of the levels above for every range read
+
+	// Range read modes, what the range reads return
+	RANGE_MODE_FULL       = "full"       // keys and values
+	RANGE_MODE_KEYS_ONLY  = "keys-only"  // keys without values
+	RANGE_MODE_COUNT_ONLY = "count-only" // only the number of keys
+
+	// Operations of the operation mix of the kv-store scenario
+	OPERATION_GET    = "get"    // read a single key
+	OPERATION_PUT    = "put"    // update a single key
+	OPERATION_DELETE = "delete" // delete a single key
+	OPERATION_RANGE  = "range"  // scan the keys below a prefix, parameters: level, mode, limit
+	OPERATION_TXN    = "txn"    // compare-and-swap transaction on several keys, parameters: keys
+	OPERATION_INSERT = "insert" // add a new key
+	OPERATION_SCAN   = "scan"   // read the keys following a key, parameters: length
+	OPERATION_RMW    = "rmw"    // read a key and write it back
+	// MVCC operations, which read past revisions of the keys
+	OPERATION_GET_REV    = "get-rev"    // read a key at a past revision, parameters: lag
+	OPERATION_PUT_PREVKV = "put-prevkv" // update a key and return its previous value
+	OPERATION_HISTORY    = "history"    // walk back through the versions of a key, parameters: depth
+
+	// Value size distributions
+	VALUE_SIZE_FIXED     = "fixed"     // every value has the value size
+	VALUE_SIZE_UNIFORM   = "uniform"   // uniformly distributed between the minimum and the maximum size
+	VALUE_SIZE_NORMAL    = "normal"    // normally distributed around the value size
+	VALUE_SIZE_LOGNORMAL = "lognormal" // log-normally distributed with the value size as median
+	VALUE_SIZE_EMPIRICAL = "empirical" // sizes of a histogram picked by their weights
+
+	// Content of the values
+	VALUE_CONTENT_RANDOM = "random" // random bytes, incompressible
+	VALUE_CONTENT_TEXT   = "text"   // English-like words, compressible
+	VALUE_CONTENT_JSON   = "json"   // JSON documents shaped like Kubernetes objects
+
+	// Read consistency of the reads of the kv-store scenario
+	READ_CONSISTENCY_LINEARIZABLE = "linearizable" // confirmed by the leader, the default of etcd
+	READ_CONSISTENCY_SERIALIZABLE = "serializable" // served by any member from its local state
+	READ_CONSISTENCY_MIXED        = "mixed"        // a share of the reads is serializable
+
+	// Key distributions, how the clients pick the keys of their operations
+	KEY_DISTRIBUTION_UNIFORM           = "uniform"           // every key is equally likely
+	KEY_DISTRIBUTION_ZIPFIAN           = "zipfian"           // the first keys are the most popular ones
+	KEY_DISTRIBUTION_SCRAMBLED_ZIPFIAN = "scrambled-zipfian" // zipfian with the popular keys spread over the key space
+	KEY_DISTRIBUTION_HOTSPOT           = "hotspot"           // a share of the operations go to a share of the keys
+	KEY_DISTRIBUTION_LATEST            = "latest"            // the last keys are the most popular ones
+	KEY_DISTRIBUTION_SEQUENTIAL        = "sequential"        // every client walks through the keys in order
+
 	// grpc
 	DEFAULT_GRPC_SERVER_PORT   = 50051
 	DEFAULT_BENCH_RUN_LOG_FILE = "run.log"
+	DEFAULT_METRICS_FILE       = "metrics.csv"
+	DEFAULT_DB_STATS_FILE      = "dbstats.csv"
+	DEFAULT_TRACE_FILE         = "trace.csv" // trace of the replay scenario sent by the control program
+	DEFAULT_FILE_CHUNK_SIZE    = 1 << 20     // 1 MiB per chunk when transferring files
+	DEFAULT_RESULTS_DIR        = "results"
+
+	// local etcd cluster started by benchctl local
+	DEFAULT_LOCAL_BASE_PORT = 2379 // client port of the first member, the peer port follows it
 
 	// metrics
 	DEFAULT_METRICS_BATCH_SIZE = 1000
+	// first line of the metrics files followed by the format version, files
+	// without it have the first format with latencies in milliseconds
+	METRICS_FORMAT_MARKER = "# csb-metrics format_version="
 )
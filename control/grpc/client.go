@@ -3,6 +3,8 @@ package grpcclient
 import (
 	"context"
 	pb "csb/api/benchmarkpb"
+	"csb/control/transfer"
+	"fmt"
 	"io"
 	"os"
 
@@ -13,6 +15,15 @@ const (
 	BATCH_SIZE = 1000
 )
 
+// ClientAssignment describes the role of a benchmark client in a run that
+// is driven against several benchmark clients at once
+type ClientAssignment struct {
+	Index          int   // position of the benchmark client in the run
+	NumClients     int   // total number of benchmark clients in the run
+	SeedOffset     int64 // offset added to the seed of the per-goroutine random generators
+	ClientIDOffset int   // offset added to the goroutine IDs reported in the metrics
+}
+
 type BenchmarkServiceClient struct {
 	client pb.BenchmarkServiceClient
 	stream pb.BenchmarkService_CTRLStreamClient
@@ -34,25 +45,69 @@ func (c *BenchmarkServiceClient) GetStream() pb.BenchmarkService_CTRLStreamClien
 	return c.stream
 }
 
-func (c *BenchmarkServiceClient) SendConfigFile(ctx context.Context, configFile string) error {
-	// Open the config file
-	file, err := os.Open(configFile)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-	// Read all config file at once
-	data, err := io.ReadAll(file)
-	if err != nil {
-		return err
-	}
+// SendConfig sends the content of a config file to the benchmark client
+func (c *BenchmarkServiceClient) SendConfig(ctx context.Context, data []byte, assignment ClientAssignment) error {
 	request := &pb.CTRLMessage{
 		Payload: &pb.CTRLMessage_ConfigFile{
 			ConfigFile: &pb.ConfigFile{
-				Content: data,
+				Content:         data,
+				ClientIndex:     int32(assignment.Index),
+				NumBenchClients: int32(assignment.NumClients),
+				SeedOffset:      assignment.SeedOffset,
+				ClientIdOffset:  int32(assignment.ClientIDOffset),
 			},
 		},
 	}
-	err = c.stream.Send(request)
-	return err
+	return c.stream.Send(request)
+}
+
+// SendTrace sends a trace file to the benchmark client in chunks, the
+// client stores it under the given name and verifies its checksum
+func (c *BenchmarkServiceClient) SendTrace(path string, name string) error {
+	return transfer.SendFile(path, name, func(chunk *pb.FileChunk) error {
+		return c.stream.Send(&pb.CTRLMessage{
+			Payload: &pb.CTRLMessage_TraceChunk{TraceChunk: chunk},
+		})
+	})
+}
+
+func (c *BenchmarkServiceClient) PullResults(ctx context.Context, outDir string, files []string) ([]string, error) {
+	return PullResults(ctx, c.client, outDir, files)
+}
+
+// PullResults downloads the given result files of a benchmark client, or all
+// of them if no files are given, into outDir and verifies their checksums,
+// it returns the paths of the written files
+func PullResults(ctx context.Context, client pb.BenchmarkServiceClient, outDir string, files []string) ([]string, error) {
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return nil, err
+	}
+	stream, err := client.PullResults(ctx, &pb.PullResultsRequest{Files: files})
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	receiver := transfer.NewReceiver(outDir)
+	// abort an incomplete transfer without leaving partial files behind
+	defer receiver.Abort()
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			if name := receiver.Pending(); name != "" {
+				return paths, fmt.Errorf("transfer of %s ended before its last chunk", name)
+			}
+			return paths, nil
+		}
+		if err != nil {
+			return paths, err
+		}
+		path, err := receiver.Receive(chunk)
+		if err != nil {
+			return paths, err
+		}
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
 }
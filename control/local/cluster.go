@@ -0,0 +1,108 @@
+package local
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.etcd.io/etcd/server/v3/embed"
+)
+
+// Cluster is an etcd cluster running in the same process, every member
+// listens on the loopback interface and keeps its data in a directory of the
+// cluster's data directory
+type Cluster struct {
+	members  []*embed.Etcd
+	dataDir  string
+	keepData bool
+}
+
+// StartCluster starts an etcd cluster with the given number of members, the
+// client and peer ports of the members are assigned in pairs starting at
+// basePort. The data is kept in dataDir if it is set, otherwise in a temporary
+// directory which is removed when the cluster is closed.
+func StartCluster(numMembers int, basePort int, dataDir string, startTimeout time.Duration) (*Cluster, error) {
+	c := &Cluster{dataDir: dataDir, keepData: dataDir != ""}
+	if !c.keepData {
+		var err error
+		c.dataDir, err = os.MkdirTemp("", "benchctl-etcd-")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create data directory: %w", err)
+		}
+	}
+
+	configs := make([]*embed.Config, numMembers)
+	initialCluster := make([]string, numMembers)
+	for i := range configs {
+		cfg := embed.NewConfig()
+		cfg.Name = fmt.Sprintf("member-%d", i)
+		cfg.Dir = filepath.Join(c.dataDir, cfg.Name)
+		clientURL := url.URL{Scheme: "http", Host: fmt.Sprintf("127.0.0.1:%d", basePort+2*i)}
+		peerURL := url.URL{Scheme: "http", Host: fmt.Sprintf("127.0.0.1:%d", basePort+2*i+1)}
+		cfg.ListenClientUrls = []url.URL{clientURL}
+		cfg.AdvertiseClientUrls = []url.URL{clientURL}
+		cfg.ListenPeerUrls = []url.URL{peerURL}
+		cfg.AdvertisePeerUrls = []url.URL{peerURL}
+		cfg.InitialClusterToken = "benchctl-local"
+		cfg.LogLevel = "error"
+		configs[i] = cfg
+		initialCluster[i] = fmt.Sprintf("%s=%s", cfg.Name, peerURL.String())
+	}
+
+	// the members only become ready once a quorum of them is running, so all
+	// of them are started before waiting for any
+	for _, cfg := range configs {
+		cfg.InitialCluster = strings.Join(initialCluster, ",")
+		member, err := embed.StartEtcd(cfg)
+		if err != nil {
+			c.Close()
+			return nil, fmt.Errorf("failed to start etcd member %s: %w", cfg.Name, err)
+		}
+		c.members = append(c.members, member)
+	}
+
+	timeout := time.After(startTimeout)
+	for _, member := range c.members {
+		select {
+		case <-member.Server.ReadyNotify():
+		case err := <-member.Err():
+			c.Close()
+			return nil, fmt.Errorf("etcd member %s failed: %w", member.Config().Name, err)
+		case <-timeout:
+			c.Close()
+			return nil, fmt.Errorf("etcd cluster is not ready after %v", startTimeout)
+		}
+	}
+	return c, nil
+}
+
+// Endpoints returns the client URLs of all members
+func (c *Cluster) Endpoints() []string {
+	endpoints := make([]string, 0, len(c.members))
+	for _, member := range c.members {
+		for _, u := range member.Config().AdvertiseClientUrls {
+			endpoints = append(endpoints, u.String())
+		}
+	}
+	return endpoints
+}
+
+// DataDir returns the directory holding the data of all members
+func (c *Cluster) DataDir() string {
+	return c.dataDir
+}
+
+// Close stops all members and removes the temporary data directory
+func (c *Cluster) Close() error {
+	for _, member := range c.members {
+		member.Close()
+	}
+	c.members = nil
+	if c.keepData {
+		return nil
+	}
+	return os.RemoveAll(c.dataDir)
+}
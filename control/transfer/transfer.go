@@ -0,0 +1,128 @@
+package transfer
+
+import (
+	"crypto/sha256"
+	pb "csb/api/benchmarkpb"
+	"csb/control/constants"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// SendFile sends the file at path in chunks under the given name, the last
+// chunk carries the sha256 checksum of the whole file. A chunk is only valid
+// until send returns.
+func SendFile(path string, name string, send func(*pb.FileChunk) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	buf := make([]byte, constants.DEFAULT_FILE_CHUNK_SIZE)
+	var offset int64
+	for {
+		n, err := io.ReadFull(file, buf)
+		last := err == io.EOF || err == io.ErrUnexpectedEOF
+		if err != nil && !last {
+			return err
+		}
+		hasher.Write(buf[:n])
+		chunk := &pb.FileChunk{
+			Name:   name,
+			Offset: offset,
+			Data:   buf[:n],
+			Last:   last,
+		}
+		if last {
+			chunk.Sha256 = hex.EncodeToString(hasher.Sum(nil))
+		}
+		if err := send(chunk); err != nil || last {
+			return err
+		}
+		offset += int64(n)
+	}
+}
+
+// Receiver writes the chunks of the files sent by SendFile into a directory.
+// A file is written next to its target with a .part suffix and only put in
+// place once its checksum is verified, a chunk at offset 0 starts a new file
+// and discards an incomplete one.
+type Receiver struct {
+	dir     string
+	file    *os.File
+	name    string // name of the file as sent
+	target  string // path of the file once it is complete
+	hasher  hash.Hash
+	written int64
+}
+
+func NewReceiver(dir string) *Receiver {
+	return &Receiver{dir: dir}
+}
+
+// Receive writes a chunk, it returns the path of the file once its last
+// chunk is received, an empty path before. The incomplete file is removed
+// if the chunk does not continue it or the checksum does not match.
+func (r *Receiver) Receive(chunk *pb.FileChunk) (string, error) {
+	if chunk.GetOffset() == 0 {
+		r.Abort()
+		r.name = chunk.GetName()
+		r.target = filepath.Join(r.dir, filepath.Base(r.name))
+		file, err := os.Create(r.target + ".part")
+		if err != nil {
+			return "", err
+		}
+		r.file, r.hasher, r.written = file, sha256.New(), 0
+	}
+	if r.file == nil || chunk.GetOffset() != r.written {
+		r.Abort()
+		return "", fmt.Errorf("unexpected chunk offset %d for %s, expected %d", chunk.GetOffset(), chunk.GetName(), r.written)
+	}
+	if _, err := r.file.Write(chunk.GetData()); err != nil {
+		r.Abort()
+		return "", err
+	}
+	r.hasher.Write(chunk.GetData())
+	r.written += int64(len(chunk.GetData()))
+	if !chunk.GetLast() {
+		return "", nil
+	}
+
+	if checksum := hex.EncodeToString(r.hasher.Sum(nil)); checksum != chunk.GetSha256() {
+		r.Abort()
+		return "", fmt.Errorf("checksum mismatch for %s: got %s, expected %s", chunk.GetName(), checksum, chunk.GetSha256())
+	}
+	file := r.file
+	r.file = nil
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+	if err := os.Rename(file.Name(), r.target); err != nil {
+		return "", err
+	}
+	return r.target, nil
+}
+
+// Pending returns the name of the file whose transfer is incomplete, an
+// empty name if there is none
+func (r *Receiver) Pending() string {
+	if r.file == nil {
+		return ""
+	}
+	return r.name
+}
+
+// Abort removes the file whose transfer is incomplete
+func (r *Receiver) Abort() {
+	if r.file != nil {
+		r.file.Close()
+		os.Remove(r.file.Name())
+		r.file = nil
+	}
+}
@@ -0,0 +1,128 @@
+package transfer
+
+import (
+	"bytes"
+	pb "csb/api/benchmarkpb"
+	"csb/control/constants"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// sendChunks sends a file and returns copies of its chunks
+func sendChunks(t *testing.T, path string, name string) []*pb.FileChunk {
+	t.Helper()
+	var chunks []*pb.FileChunk
+	err := SendFile(path, name, func(chunk *pb.FileChunk) error {
+		chunk.Data = bytes.Clone(chunk.Data)
+		chunks = append(chunks, chunk)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("SendFile() error = %v", err)
+	}
+	return chunks
+}
+
+func TestTransferRoundTrip(t *testing.T) {
+	src := t.TempDir()
+	data := make([]byte, 2*constants.DEFAULT_FILE_CHUNK_SIZE+100)
+	rand.New(rand.NewSource(1)).Read(data)
+	path := filepath.Join(src, "metrics.csv")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	empty := filepath.Join(src, "empty.csv")
+	if err := os.WriteFile(empty, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Test that a file is only put in place with its last chunk
+	dst := t.TempDir()
+	receiver := NewReceiver(dst)
+	chunks := sendChunks(t, path, "metrics.csv")
+	if len(chunks) != 3 {
+		t.Fatalf("SendFile() sent %d chunks, want 3", len(chunks))
+	}
+	for i, chunk := range chunks {
+		got, err := receiver.Receive(chunk)
+		if err != nil {
+			t.Fatalf("Receive() error = %v", err)
+		}
+		if last := i == len(chunks)-1; (got != "") != last {
+			t.Errorf("Receive() of chunk %d = %q", i, got)
+		}
+	}
+	received, err := os.ReadFile(filepath.Join(dst, "metrics.csv"))
+	if err != nil || !bytes.Equal(received, data) {
+		t.Errorf("received file differs from the sent file, error = %v", err)
+	}
+
+	// Test that an empty file is transferred as a single chunk
+	chunks = sendChunks(t, empty, "empty.csv")
+	if got, err := receiver.Receive(chunks[0]); err != nil || got != filepath.Join(dst, "empty.csv") {
+		t.Errorf("Receive() = %q, %v, want the empty file", got, err)
+	}
+	if receiver.Pending() != "" {
+		t.Errorf("Pending() = %q after complete transfers", receiver.Pending())
+	}
+}
+
+func TestTransferRejectsCorruptFiles(t *testing.T) {
+	src := t.TempDir()
+	path := filepath.Join(src, "trace.csv")
+	data := make([]byte, constants.DEFAULT_FILE_CHUNK_SIZE+10)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	chunks := sendChunks(t, path, "trace.csv")
+
+	tests := []struct {
+		name    string
+		corrupt func(chunks []*pb.FileChunk) []*pb.FileChunk
+		wantErr string
+	}{
+		{
+			name: "checksum mismatch",
+			corrupt: func(chunks []*pb.FileChunk) []*pb.FileChunk {
+				chunks[1].Data[0] ^= 1
+				return chunks
+			},
+			wantErr: "checksum mismatch",
+		},
+		{
+			name: "missing chunk",
+			corrupt: func(chunks []*pb.FileChunk) []*pb.FileChunk {
+				chunks[1].Offset++
+				return chunks
+			},
+			wantErr: "unexpected chunk offset",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := t.TempDir()
+			receiver := NewReceiver(dst)
+			corrupted := make([]*pb.FileChunk, len(chunks))
+			for i, chunk := range chunks {
+				corrupted[i] = &pb.FileChunk{Name: chunk.Name, Offset: chunk.Offset, Data: bytes.Clone(chunk.Data), Last: chunk.Last, Sha256: chunk.Sha256}
+			}
+			var err error
+			for _, chunk := range tt.corrupt(corrupted) {
+				if _, err = receiver.Receive(chunk); err != nil {
+					break
+				}
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Receive() error = %v, want %q", err, tt.wantErr)
+			}
+			// Test that no file is left behind
+			if entries, _ := os.ReadDir(dst); len(entries) != 0 {
+				t.Errorf("receiver left %d files behind", len(entries))
+			}
+		})
+	}
+}
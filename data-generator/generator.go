@@ -83,18 +83,24 @@ func (g *Generator) NewRand(seed int64, id int) *rand.Rand {
 
 // generateUniquePadding creates a unique padding for a given prefix
 func (g *Generator) generateUniquePadding(paddingSize int, prefix string) string {
-	const charPool = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 	for {
-		padding := make([]byte, paddingSize)
-		for i := range padding {
-			padding[i] = charPool[g.rg.Intn(len(charPool))]
-		}
-		if !g.isUsedPadding(prefix, string(padding)) {
-			return string(padding)
+		padding := randomPadding(g.rg, paddingSize)
+		if !g.isUsedPadding(prefix, padding) {
+			return padding
 		}
 	}
 }
 
+// randomPadding creates a padding of random alphanumeric characters
+func randomPadding(rg *rand.Rand, paddingSize int) string {
+	const charPool = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	padding := make([]byte, paddingSize)
+	for i := range padding {
+		padding[i] = charPool[rg.Intn(len(charPool))]
+	}
+	return string(padding)
+}
+
 // isUsedPadding checks if a padding has been used for a given prefix
 func (g *Generator) isUsedPadding(prefix string, padding string) bool {
 	parts := strings.Split(strings.Trim(prefix, "/"), "/")
@@ -123,20 +129,10 @@ func (g *Generator) isUsedPadding(prefix string, padding string) bool {
 
 // GenerateKey creates an etcd key following K8s format
 func (g *Generator) GenerateKey(targetSize int) (string, error) {
-	if targetSize < constants.MIN_KEY_SIZE {
-		return "", fmt.Errorf("target size %d is less than minimum required size %d", targetSize, constants.MIN_KEY_SIZE)
+	prefix, paddingSize, err := randomKeyPrefix(g.rg, targetSize)
+	if err != nil {
+		return "", err
 	}
-
-	domain := domains[g.rg.Intn(len(domains))]
-	region := regions[g.rg.Intn(len(regions))]
-	shard := fmt.Sprintf("%03d", g.rg.Intn(1000))
-
-	// Calculate required padding size
-	// prefix = slash + domain + slash + region + slash + shard = 12 bytes
-	prefixLen := 1 + len(domain) + 1 + len(region) + 1 + len(shard)
-	paddingSize := targetSize - prefixLen
-
-	prefix := fmt.Sprintf("/%s/%s/%s", domain, region, shard)
 	if paddingSize == 0 {
 		if !g.isUsedPadding(prefix, "") {
 			return prefix, nil
@@ -149,6 +145,55 @@ func (g *Generator) GenerateKey(targetSize int) (string, error) {
 	return prefix + padding, nil
 }
 
+// RandomKey creates a key in the format of GenerateKey from the given random
+// generator. Unlike GenerateKey it does not remember the keys it created, so
+// the caller has to check whether a key is new.
+func RandomKey(rg *rand.Rand, targetSize int) (string, error) {
+	prefix, paddingSize, err := randomKeyPrefix(rg, targetSize)
+	if err != nil {
+		return "", err
+	}
+	return prefix + randomPadding(rg, paddingSize), nil
+}
+
+// randomKeyPrefix picks the domain, region and shard of a key, it returns the
+// prefix and the size of the padding which fills up the key
+func randomKeyPrefix(rg *rand.Rand, targetSize int) (string, int, error) {
+	if targetSize < constants.MIN_KEY_SIZE {
+		return "", 0, fmt.Errorf("target size %d is less than minimum required size %d", targetSize, constants.MIN_KEY_SIZE)
+	}
+
+	domain := domains[rg.Intn(len(domains))]
+	region := regions[rg.Intn(len(regions))]
+	shard := fmt.Sprintf("%03d", rg.Intn(1000))
+
+	// Calculate required padding size
+	// prefix = slash + domain + slash + region + slash + shard = 12 bytes
+	prefixLen := 1 + len(domain) + 1 + len(region) + 1 + len(shard)
+	prefix := fmt.Sprintf("/%s/%s/%s", domain, region, shard)
+	return prefix, targetSize - prefixLen, nil
+}
+
+// KeyPrefix returns the prefix of a key at a level of the key hierarchy,
+// i.e. "/domain/", "/domain/region/" or "/domain/region/shard"
+func KeyPrefix(key string, level string) (string, error) {
+	var prefixLen int
+	switch level {
+	case constants.RANGE_LEVEL_DOMAIN:
+		prefixLen = 1 + 3 + 1
+	case constants.RANGE_LEVEL_REGION:
+		prefixLen = 1 + 3 + 1 + 3 + 1
+	case constants.RANGE_LEVEL_SHARD:
+		prefixLen = constants.MIN_KEY_SIZE
+	default:
+		return "", fmt.Errorf("unknown key level %s", level)
+	}
+	if len(key) < prefixLen {
+		return "", fmt.Errorf("key %s is shorter than its %s prefix", key, level)
+	}
+	return key[:prefixLen], nil
+}
+
 // GenerateValue creates a synthetic value for the resource
 func (g *Generator) GenerateValue(targetBytes int, rg *rand.Rand) ([]byte, error) {
 	if rg == nil {
@@ -162,7 +207,9 @@ func (g *Generator) GenerateValue(targetBytes int, rg *rand.Rand) ([]byte, error
 	return result, nil
 }
 
-func (g *Generator) GenerateData(count int, keySize int, valueSize int) (map[string][]byte, error) {
+// GenerateData creates the given number of key-value pairs, the values are
+// drawn from the value generator
+func (g *Generator) GenerateData(count int, keySize int, values *ValueGenerator) (map[string][]byte, error) {
 	rand := g.rg
 	uuid.SetRand(rand)
 	data := make(map[string][]byte)
@@ -182,8 +229,8 @@ func (g *Generator) GenerateData(count int, keySize int, valueSize int) (map[str
 			return nil, err
 		}
 
-		// Generate value of a given size in bytes
-		value, err := g.GenerateValue(valueSize, nil)
+		// Generate value with a size drawn from the distribution
+		value, err := values.Generate(g.rg)
 		if err != nil {
 			return nil, err
 		}
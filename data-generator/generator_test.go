@@ -3,6 +3,9 @@ package generator
 import (
 	"bytes"
 	config "csb/control/config"
+	"csb/control/constants"
+	"encoding/json"
+	"math"
 	"math/rand"
 	"reflect"
 	"sort"
@@ -27,15 +30,20 @@ func TestGenerateDataDeterminism(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
+			values, err := NewValueGenerator(ValueOptions{Size: tc.valueSize})
+			if err != nil {
+				t.Fatalf("NewValueGenerator() error = %v", err)
+			}
+
 			// First execution
 			rg1 := rand.New(rand.NewSource(tc.seed))
 			gen1 := NewGenerator(rg1)
-			data1, _ := gen1.GenerateData(tc.count, tc.keySize, tc.valueSize)
+			data1, _ := gen1.GenerateData(tc.count, tc.keySize, values)
 
 			// Second execution
 			rg2 := rand.New(rand.NewSource(tc.seed))
 			gen2 := NewGenerator(rg2)
-			data2, _ := gen2.GenerateData(tc.count, tc.keySize, tc.valueSize)
+			data2, _ := gen2.GenerateData(tc.count, tc.keySize, values)
 
 			// Check if both executions generated the same number of items
 			if len(data1) != len(data2) {
@@ -81,3 +89,192 @@ func getOrderedKeys(data map[string][]byte) []string {
 	sort.Strings(keys)
 	return keys
 }
+
+func TestKeyPrefix(t *testing.T) {
+	testCases := []struct {
+		level   string
+		want    string
+		wantErr bool
+	}{
+		{constants.RANGE_LEVEL_DOMAIN, "/prd/", false},
+		{constants.RANGE_LEVEL_REGION, "/prd/eu4/", false},
+		{constants.RANGE_LEVEL_SHARD, "/prd/eu4/637", false},
+		{"cluster", "", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.level, func(t *testing.T) {
+			got, err := KeyPrefix("/prd/eu4/637cGxJ", tc.level)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("KeyPrefix() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("KeyPrefix() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRandomKey(t *testing.T) {
+	// Test that the keys only depend on the random generator
+	rg1 := rand.New(rand.NewSource(42))
+	rg2 := rand.New(rand.NewSource(42))
+	for i := 0; i < 100; i++ {
+		key1, err := RandomKey(rg1, 20)
+		if err != nil {
+			t.Fatalf("RandomKey() error = %v", err)
+		}
+		key2, _ := RandomKey(rg2, 20)
+		if key1 != key2 {
+			t.Fatalf("RandomKey() = %q and %q with the same seed", key1, key2)
+		}
+		if len(key1) != 20 {
+			t.Errorf("RandomKey() = %q, want 20 bytes", key1)
+		}
+		if _, err := KeyPrefix(key1, constants.RANGE_LEVEL_SHARD); err != nil {
+			t.Errorf("KeyPrefix() error = %v", err)
+		}
+	}
+
+	// Test a key size below the minimum
+	if _, err := RandomKey(rg1, constants.MIN_KEY_SIZE-1); err == nil {
+		t.Error("RandomKey() expected error for too small key size")
+	}
+}
+
+func TestValueSizeDistributions(t *testing.T) {
+	const samples = 20000
+	testCases := []struct {
+		name       string
+		opts       ValueOptions
+		wantMin    int
+		wantMax    int
+		wantMedian float64 // 0 to skip the check
+	}{
+		{"fixed", ValueOptions{Distribution: constants.VALUE_SIZE_FIXED, Size: 256}, 256, 256, 256},
+		{"uniform", ValueOptions{Distribution: constants.VALUE_SIZE_UNIFORM, MinSize: 100, MaxSize: 300}, 100, 300, 200},
+		{"normal", ValueOptions{Distribution: constants.VALUE_SIZE_NORMAL, Size: 1000, StdDev: 100}, 0, math.MaxInt, 1000},
+		{"normal bounded", ValueOptions{Distribution: constants.VALUE_SIZE_NORMAL, Size: 1000, StdDev: 500, MinSize: 800, MaxSize: 1200}, 800, 1200, 1000},
+		{"lognormal", ValueOptions{Distribution: constants.VALUE_SIZE_LOGNORMAL, Size: 512, Sigma: 1}, 0, math.MaxInt, 512},
+		{"empirical", ValueOptions{Distribution: constants.VALUE_SIZE_EMPIRICAL, Sizes: []int{10, 1000, 5000}, Weights: []int{0, 3, 1}}, 1000, 5000, 1000},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			vg, err := NewValueGenerator(tc.opts)
+			if err != nil {
+				t.Fatalf("NewValueGenerator() error = %v", err)
+			}
+			rg := rand.New(rand.NewSource(1))
+			sizes := make([]int, samples)
+			for i := range sizes {
+				sizes[i] = vg.Size(rg)
+				if sizes[i] < tc.wantMin || sizes[i] > tc.wantMax {
+					t.Fatalf("Size() = %d, want between %d and %d", sizes[i], tc.wantMin, tc.wantMax)
+				}
+			}
+			sort.Ints(sizes)
+			median := float64(sizes[samples/2])
+			if math.Abs(median-tc.wantMedian) > 0.05*tc.wantMedian {
+				t.Errorf("median size = %v, want about %v", median, tc.wantMedian)
+			}
+		})
+	}
+}
+
+func TestNewValueGeneratorInvalid(t *testing.T) {
+	testCases := []struct {
+		name string
+		opts ValueOptions
+	}{
+		{"unknown distribution", ValueOptions{Distribution: "pareto", Size: 10}},
+		{"uniform without range", ValueOptions{Distribution: constants.VALUE_SIZE_UNIFORM, MinSize: 10, MaxSize: 5}},
+		{"lognormal without median", ValueOptions{Distribution: constants.VALUE_SIZE_LOGNORMAL}},
+		{"empirical without weights", ValueOptions{Distribution: constants.VALUE_SIZE_EMPIRICAL, Sizes: []int{10}}},
+		{"empirical with zero weights", ValueOptions{Distribution: constants.VALUE_SIZE_EMPIRICAL, Sizes: []int{10}, Weights: []int{0}}},
+		{"unknown content", ValueOptions{Size: 10, Content: "xml"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewValueGenerator(tc.opts); err == nil {
+				t.Error("NewValueGenerator() expected an error")
+			}
+		})
+	}
+}
+
+func TestGenerateContent(t *testing.T) {
+	rg := rand.New(rand.NewSource(1))
+	for _, size := range []int{0, 1, 50, 400, 4096} {
+		for _, content := range []string{constants.VALUE_CONTENT_RANDOM, constants.VALUE_CONTENT_TEXT, constants.VALUE_CONTENT_JSON} {
+			value, err := GenerateContent(content, size, rg)
+			if err != nil {
+				t.Fatalf("GenerateContent(%s, %d) error = %v", content, size, err)
+			}
+			if len(value) != size {
+				t.Errorf("GenerateContent(%s, %d) returned %d bytes", content, size, len(value))
+			}
+		}
+	}
+
+	// documents large enough for the object are valid JSON
+	value, err := GenerateContent(constants.VALUE_CONTENT_JSON, 4096, rg)
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	var obj map[string]any
+	if err := json.Unmarshal(value, &obj); err != nil {
+		t.Fatalf("JSON value is invalid: %v", err)
+	}
+	for _, field := range []string{"apiVersion", "kind", "metadata", "data"} {
+		if _, ok := obj[field]; !ok {
+			t.Errorf("JSON value has no field %s", field)
+		}
+	}
+}
+
+func TestGenerateObjects(t *testing.T) {
+	values, err := NewValueGenerator(ValueOptions{Size: 64})
+	if err != nil {
+		t.Fatalf("NewValueGenerator() error = %v", err)
+	}
+	counts := []ObjectCount{{Resource: "pods", Count: 30}, {Resource: "nodes", Count: 5}}
+	data, err := NewGenerator(rand.New(rand.NewSource(1))).GenerateObjects(counts, 3, values)
+	if err != nil {
+		t.Fatalf("GenerateObjects() error = %v", err)
+	}
+	if len(data) != 35 {
+		t.Fatalf("GenerateObjects() generated %d objects, want 35", len(data))
+	}
+
+	// namespaced objects are spread over the namespaces, cluster-scoped
+	// objects have no namespace
+	perPrefix := make(map[string]int)
+	for key := range data {
+		prefix, err := ObjectPrefix(key, true)
+		if err != nil {
+			t.Fatalf("ObjectPrefix(%s) error = %v", key, err)
+		}
+		perPrefix[prefix]++
+	}
+	want := map[string]int{
+		"/registry/pods/ns-000/": 10,
+		"/registry/pods/ns-001/": 10,
+		"/registry/pods/ns-002/": 10,
+		"/registry/nodes/":       5,
+	}
+	if !reflect.DeepEqual(perPrefix, want) {
+		t.Errorf("objects per prefix = %v, want %v", perPrefix, want)
+	}
+	if _, ok := data["/registry/pods/ns-001/pod-000001"]; !ok {
+		t.Error("object /registry/pods/ns-001/pod-000001 is missing")
+	}
+	if _, ok := data["/registry/nodes/node-000004"]; !ok {
+		t.Error("object /registry/nodes/node-000004 is missing")
+	}
+
+	if _, err := NewGenerator(rand.New(rand.NewSource(1))).GenerateObjects(counts, 0, values); err == nil {
+		t.Error("GenerateObjects() without namespaces succeeded")
+	}
+}
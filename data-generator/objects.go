@@ -0,0 +1,100 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Prefix of the keys of the Kubernetes objects, the keys follow the layout
+// of the kube-apiserver: /registry/<resource>/<namespace>/<name> for
+// namespaced resources and /registry/<resource>/<name> for cluster-scoped
+// ones
+const ObjectKeyPrefix = "/registry/"
+
+// Resources which are not bound to a namespace
+var clusterScopedResources = map[string]bool{
+	"nodes":                     true,
+	"namespaces":                true,
+	"persistentvolumes":         true,
+	"clusterroles":              true,
+	"clusterrolebindings":       true,
+	"customresourcedefinitions": true,
+	"storageclasses":            true,
+	"priorityclasses":           true,
+}
+
+// ObjectCount is the number of objects of a resource type
+type ObjectCount struct {
+	Resource string
+	Count    int
+}
+
+// IsClusterScoped reports whether the objects of the resource are not bound
+// to a namespace
+func IsClusterScoped(resource string) bool {
+	return clusterScopedResources[resource]
+}
+
+// ObjectKey returns the key of an object, the namespace is ignored for
+// cluster-scoped resources
+func ObjectKey(resource string, namespace string, name string) string {
+	if IsClusterScoped(resource) {
+		return ObjectKeyPrefix + resource + "/" + name
+	}
+	return ObjectKeyPrefix + resource + "/" + namespace + "/" + name
+}
+
+// ObjectName returns the name of an object of the resource with the given
+// ID, e.g. "pod-000042" for the ID "000042" of a pod
+func ObjectName(resource string, id string) string {
+	return strings.TrimSuffix(resource, "s") + "-" + id
+}
+
+// ObjectResource returns the resource type of the key of an object
+func ObjectResource(key string) (string, error) {
+	resource, _, ok := strings.Cut(strings.TrimPrefix(key, ObjectKeyPrefix), "/")
+	if !strings.HasPrefix(key, ObjectKeyPrefix) || !ok {
+		return "", fmt.Errorf("key %s is not the key of an object", key)
+	}
+	return resource, nil
+}
+
+// Namespace returns the name of the namespace with the given index
+func Namespace(index int) string {
+	return fmt.Sprintf("ns-%03d", index)
+}
+
+// ObjectPrefix returns the prefix of the objects of the resource of a key,
+// "/registry/<resource>/" or with the namespace of the key
+// "/registry/<resource>/<namespace>/" if it is namespaced
+func ObjectPrefix(key string, withNamespace bool) (string, error) {
+	parts := strings.SplitN(strings.TrimPrefix(key, ObjectKeyPrefix), "/", 3)
+	if !strings.HasPrefix(key, ObjectKeyPrefix) || len(parts) < 2 {
+		return "", fmt.Errorf("key %s is not the key of an object", key)
+	}
+	if withNamespace && len(parts) == 3 {
+		return ObjectKeyPrefix + parts[0] + "/" + parts[1] + "/", nil
+	}
+	return ObjectKeyPrefix + parts[0] + "/", nil
+}
+
+// GenerateObjects creates the given number of objects of every resource
+// type, the namespaced objects are spread evenly over the namespaces and
+// the values are drawn from the value generator
+func (g *Generator) GenerateObjects(counts []ObjectCount, namespaces int, values *ValueGenerator) (map[string][]byte, error) {
+	if namespaces <= 0 {
+		return nil, fmt.Errorf("number of namespaces %d has to be positive", namespaces)
+	}
+	data := make(map[string][]byte)
+	for _, c := range counts {
+		for i := 0; i < c.Count; i++ {
+			name := ObjectName(c.Resource, fmt.Sprintf("%06d", i))
+			value, err := values.Generate(g.rg)
+			if err != nil {
+				return nil, err
+			}
+			data[ObjectKey(c.Resource, Namespace(i%namespaces), name)] = value
+		}
+	}
+	return data, nil
+}
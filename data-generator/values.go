@@ -0,0 +1,208 @@
+package generator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"csb/control/constants"
+
+	"github.com/google/uuid"
+)
+
+// ValueOptions describe the sizes and the content of generated values
+type ValueOptions struct {
+	Distribution string  // one of the value size distributions, empty is fixed
+	Size         int     // fixed size, mean of normal and median of lognormal sizes
+	MinSize      int     // smallest size, also the lower bound of uniform sizes
+	MaxSize      int     // largest size, also the upper bound of uniform sizes, 0 for no limit
+	StdDev       float64 // standard deviation of normal sizes in bytes
+	Sigma        float64 // standard deviation of the logarithm of lognormal sizes
+	Sizes        []int   // sizes of the empirical distribution
+	Weights      []int   // weights of the sizes of the empirical distribution
+	Content      string  // one of the value contents, empty is random
+}
+
+// ValueGenerator generates values with sizes drawn from a distribution, it
+// is safe for concurrent use as long as every goroutine has its own random
+// generator
+type ValueGenerator struct {
+	opts ValueOptions
+	// cumulative weights of the empirical sizes
+	cumWeights  []int
+	totalWeight int
+}
+
+func NewValueGenerator(opts ValueOptions) (*ValueGenerator, error) {
+	vg := &ValueGenerator{opts: opts}
+	switch opts.Distribution {
+	case "", constants.VALUE_SIZE_FIXED, constants.VALUE_SIZE_NORMAL:
+	case constants.VALUE_SIZE_UNIFORM:
+		if opts.MaxSize < opts.MinSize {
+			return nil, fmt.Errorf("maximum value size %d is below the minimum value size %d", opts.MaxSize, opts.MinSize)
+		}
+	case constants.VALUE_SIZE_LOGNORMAL:
+		if opts.Size <= 0 {
+			return nil, errors.New("the median of lognormal value sizes has to be positive")
+		}
+	case constants.VALUE_SIZE_EMPIRICAL:
+		if len(opts.Sizes) == 0 || len(opts.Sizes) != len(opts.Weights) {
+			return nil, errors.New("empirical value sizes need a weight for every size")
+		}
+		for _, weight := range opts.Weights {
+			vg.totalWeight += weight
+			vg.cumWeights = append(vg.cumWeights, vg.totalWeight)
+		}
+		if vg.totalWeight <= 0 {
+			return nil, errors.New("empirical value sizes have no positive weight")
+		}
+	default:
+		return nil, fmt.Errorf("unknown value size distribution %s", opts.Distribution)
+	}
+	switch opts.Content {
+	case "", constants.VALUE_CONTENT_RANDOM, constants.VALUE_CONTENT_TEXT, constants.VALUE_CONTENT_JSON:
+	default:
+		return nil, fmt.Errorf("unknown value content %s", opts.Content)
+	}
+	return vg, nil
+}
+
+// Size draws the size of the next value
+func (vg *ValueGenerator) Size(rg *rand.Rand) int {
+	var size float64
+	switch vg.opts.Distribution {
+	case constants.VALUE_SIZE_UNIFORM:
+		return vg.opts.MinSize + rg.Intn(vg.opts.MaxSize-vg.opts.MinSize+1)
+	case constants.VALUE_SIZE_NORMAL:
+		size = float64(vg.opts.Size) + rg.NormFloat64()*vg.opts.StdDev
+	case constants.VALUE_SIZE_LOGNORMAL:
+		size = float64(vg.opts.Size) * math.Exp(rg.NormFloat64()*vg.opts.Sigma)
+	case constants.VALUE_SIZE_EMPIRICAL:
+		n := rg.Intn(vg.totalWeight)
+		return vg.opts.Sizes[sort.SearchInts(vg.cumWeights, n+1)]
+	default:
+		return vg.opts.Size
+	}
+	size = max(math.Round(size), float64(vg.opts.MinSize), 0)
+	if vg.opts.MaxSize > 0 {
+		size = min(size, float64(vg.opts.MaxSize))
+	}
+	return int(size)
+}
+
+// Generate creates the next value, its size is drawn from the distribution
+func (vg *ValueGenerator) Generate(rg *rand.Rand) ([]byte, error) {
+	return GenerateContent(vg.opts.Content, vg.Size(rg), rg)
+}
+
+// GenerateSize creates a value of the given size with the content of the
+// generator, a negative size is drawn from the distribution
+func (vg *ValueGenerator) GenerateSize(size int, rg *rand.Rand) ([]byte, error) {
+	if size < 0 {
+		size = vg.Size(rg)
+	}
+	return GenerateContent(vg.opts.Content, size, rg)
+}
+
+// GenerateContent creates a value of exactly the given size with the given
+// content
+func GenerateContent(content string, size int, rg *rand.Rand) ([]byte, error) {
+	switch content {
+	case constants.VALUE_CONTENT_TEXT:
+		return generateText(size, rg), nil
+	case constants.VALUE_CONTENT_JSON:
+		return generateJSON(size, rg)
+	default:
+		value := make([]byte, size)
+		if _, err := rg.Read(value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+}
+
+// Words of the generated text, a small vocabulary keeps the text about as
+// compressible as English prose
+var words = strings.Fields(`the of and to in is that for it as with was on be by this are from at or
+an have not which but all were they their one has more can will would there been if its about
+other when into some time only new these two may first then any also after such most where over
+service cluster node request value version config data system user order product region update
+status event record memory storage network replica leader follower revision lease watch key`)
+
+// generateText returns words separated by spaces, cut to the given size
+func generateText(size int, rg *rand.Rand) []byte {
+	var sb strings.Builder
+	sb.Grow(size + 16)
+	for sb.Len() < size {
+		if sb.Len() > 0 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(words[rg.Intn(len(words))])
+	}
+	return []byte(sb.String()[:size])
+}
+
+// Kinds of the generated Kubernetes objects
+var objectKinds = []struct{ apiVersion, kind string }{
+	{"v1", "ConfigMap"},
+	{"v1", "Secret"},
+	{"v1", "Event"},
+	{"apps/v1", "Deployment"},
+	{"coordination.k8s.io/v1", "Lease"},
+}
+
+// k8sObject is the shape of the generated JSON documents, the data holds
+// the payload which pads the document to its size
+type k8sObject struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Metadata   k8sObjectMeta     `json:"metadata"`
+	Data       map[string]string `json:"data"`
+}
+
+type k8sObjectMeta struct {
+	Name              string            `json:"name"`
+	Namespace         string            `json:"namespace"`
+	UID               string            `json:"uid"`
+	ResourceVersion   string            `json:"resourceVersion"`
+	CreationTimestamp string            `json:"creationTimestamp"`
+	Labels            map[string]string `json:"labels"`
+}
+
+// generateJSON returns a JSON document shaped like a Kubernetes object of
+// exactly the given size, values too small for the document hold text
+func generateJSON(size int, rg *rand.Rand) ([]byte, error) {
+	kind := objectKinds[rg.Intn(len(objectKinds))]
+	uid, err := uuid.NewRandomFromReader(rg)
+	if err != nil {
+		return nil, err
+	}
+	obj := k8sObject{
+		APIVersion: kind.apiVersion,
+		Kind:       kind.kind,
+		Metadata: k8sObjectMeta{
+			Name:              fmt.Sprintf("%s-%s", words[rg.Intn(len(words))], uid.String()[:8]),
+			Namespace:         words[rg.Intn(len(words))],
+			UID:               uid.String(),
+			ResourceVersion:   fmt.Sprint(rg.Intn(1 << 30)),
+			CreationTimestamp: time.Unix(1577836800+rg.Int63n(1<<27), 0).UTC().Format(time.RFC3339),
+			Labels:            map[string]string{"app": words[rg.Intn(len(words))]},
+		},
+		Data: map[string]string{"payload": ""},
+	}
+	doc, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	if len(doc) > size {
+		return generateText(size, rg), nil
+	}
+	// the text of the payload needs no escaping, so it adds its own length
+	obj.Data["payload"] = string(generateText(size-len(doc), rg))
+	return json.Marshal(obj)
+}